@@ -2,20 +2,40 @@ package zerobus
 
 import (
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // RecordAck represents a pending acknowledgment for an ingested record.
 // It allows ingestion with deferred acknowledgment handling.
 type RecordAck struct {
-	ackID  uint64
-	once   sync.Once
-	offset int64
-	err    error
+	ackID      uint64
+	options    *StreamConfigurationOptions
+	observer   Observer
+	stream     *ZerobusStream
+	queuedAt   time.Time
+	once       sync.Once
+	offset     int64
+	err        error
+	notifyOnce sync.Once
+	doneOnce   sync.Once
+	done       chan struct{}
 }
 
+// streamAwaitAckFn and streamTryGetAckFn indirect the FFI calls RecordAck
+// depends on so tests can substitute a fake stream without going through
+// CGo; production code always leaves them pointing at the real functions.
+var (
+	streamAwaitAckFn  = streamAwaitAck
+	streamTryGetAckFn = streamTryGetAck
+)
+
 // Await blocks until the record is acknowledged by the server and returns the offset.
 // This method can only be called once. Subsequent calls return the cached result.
 //
+// Retryable errors are retried on the stream's configured backoff schedule
+// (see StreamConfigurationOptions.Backoff) before being returned to the caller.
+//
 // Example:
 //
 //	ack, _ := stream.IngestRecord(data)
@@ -23,15 +43,64 @@ type RecordAck struct {
 //	offset, err := ack.Await()
 func (a *RecordAck) Await() (int64, error) {
 	a.once.Do(func() {
-		a.offset, a.err = streamAwaitAck(a.ackID)
+		a.offset, a.err = retryOnRecoverable(a.options, func() (int64, error) {
+			return streamAwaitAckFn(a.ackID)
+		})
+		a.resolved(a.offset, a.err)
 	})
 	return a.offset, a.err
 }
 
+// resolved reports offset/err to the Observer and removes this ack from the
+// stream's best-effort inflight count, exactly once regardless of which
+// method (Await, AwaitContext) observed the result first.
+func (a *RecordAck) resolved(offset int64, err error) {
+	a.notifyOnce.Do(func() {
+		if a.stream != nil {
+			atomic.AddInt64(&a.stream.inflight, -1)
+		}
+		if a.observer != nil {
+			a.observer.OnRecordAcked(a.ackID, offset, time.Since(a.queuedAt))
+		}
+	})
+}
+
+// abandonAcks resolves every non-nil ack in acks with err, releasing their
+// inflight/Observer accounting for RecordAcks the caller will never get to
+// see — e.g. a multi-chunk batch where earlier chunks were already durably
+// queued (incrementing inflight and firing OnRecordQueued via newAck) but a
+// later chunk failed, so the whole call returns an error and those acks are
+// discarded rather than returned. Any eventual server-side acknowledgment
+// for them is simply dropped, since nothing holds a reference to the ack
+// anymore to observe it.
+func abandonAcks(acks []*RecordAck, err error) {
+	for _, a := range acks {
+		if a != nil {
+			a.resolved(0, err)
+		}
+	}
+}
+
+// Done returns a channel that's closed once the acknowledgment is ready, so
+// callers can select on many RecordAcks (and their own shutdown signals)
+// at once instead of blocking on Await one at a time. The first call to
+// Done starts a background goroutine that waits on Await; later calls
+// return the same channel.
+func (a *RecordAck) Done() <-chan struct{} {
+	a.doneOnce.Do(func() {
+		a.done = make(chan struct{})
+		go func() {
+			a.Await()
+			close(a.done)
+		}()
+	})
+	return a.done
+}
+
 // TryGet attempts to get the acknowledgment without blocking.
 // Returns (offset, nil, true) if the acknowledgment is ready.
 // Returns (0, nil, false) if still pending.
 // Returns (0, error, true) if there was an error.
 func (a *RecordAck) TryGet() (int64, error, bool) {
-	return streamTryGetAck(a.ackID)
+	return streamTryGetAckFn(a.ackID)
 }
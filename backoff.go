@@ -0,0 +1,127 @@
+package zerobus
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff computes the delay to wait before the next retry attempt.
+// Implementations are called with the zero-based attempt number (0 for the
+// first retry) and must be safe for concurrent use.
+type Backoff interface {
+	// Backoff returns how long to wait before retrying after the given
+	// number of prior attempts.
+	Backoff(retries int) time.Duration
+}
+
+// BackoffConfig implements Backoff using the exponential-backoff-with-jitter
+// strategy described by gRPC's connection backoff spec:
+// https://github.com/grpc/grpc/blob/master/doc/connection-backoff.md
+//
+// The delay grows as BaseDelay * Multiplier^retries, capped at MaxDelay, and
+// is then perturbed by a uniform random factor in [1-Jitter, 1+Jitter] so that
+// many clients retrying at once don't stay in lockstep.
+type BackoffConfig struct {
+	// BaseDelay is the delay used for the first retry attempt.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the computed delay regardless of the attempt count.
+	MaxDelay time.Duration
+
+	// Multiplier is applied to the delay after each failed attempt.
+	Multiplier float64
+
+	// Jitter is the fraction by which the computed delay may be randomly
+	// scaled up or down, e.g. 0.2 perturbs the delay by +/-20%.
+	Jitter float64
+}
+
+// DefaultBackoffConfig is the backoff strategy used when
+// StreamConfigurationOptions.Backoff is left unset.
+func DefaultBackoffConfig() *BackoffConfig {
+	return &BackoffConfig{
+		BaseDelay:  1 * time.Second,
+		MaxDelay:   120 * time.Second,
+		Multiplier: 1.6,
+		Jitter:     0.2,
+	}
+}
+
+// Backoff returns the delay to use before the retry numbered by retries
+// (0-indexed), per the formula documented on BackoffConfig.
+func (c *BackoffConfig) Backoff(retries int) time.Duration {
+	if retries < 0 {
+		retries = 0
+	}
+
+	delay := float64(c.BaseDelay)
+	for i := 0; i < retries && delay < float64(c.MaxDelay); i++ {
+		delay *= c.Multiplier
+	}
+	if max := float64(c.MaxDelay); delay > max {
+		delay = max
+	}
+
+	if c.Jitter > 0 {
+		delta := c.Jitter * delay
+		delay += delta*rand.Float64()*2 - delta
+	}
+
+	return time.Duration(delay)
+}
+
+// backoffFor resolves the Backoff strategy to use for the given options,
+// falling back to a flat delay derived from RecoveryBackoffMs when the
+// caller hasn't configured one.
+func backoffFor(options *StreamConfigurationOptions) Backoff {
+	if options != nil && options.Backoff != nil {
+		return options.Backoff
+	}
+
+	backoffMs := uint64(2000)
+	if options != nil {
+		backoffMs = options.RecoveryBackoffMs
+	}
+	return &BackoffConfig{
+		BaseDelay:  time.Duration(backoffMs) * time.Millisecond,
+		MaxDelay:   time.Duration(backoffMs) * time.Millisecond,
+		Multiplier: 1,
+	}
+}
+
+// retryOnRecoverable runs fn, retrying with backoffFor(options) between
+// attempts whenever fn returns a retryable *ZerobusError. Retries stop once
+// options.RecoveryRetries attempts have been made or fn succeeds.
+//
+// This retry happens entirely on the Go side, independent of any recovery
+// the underlying FFI layer performs internally, so that reconnects and ack
+// waits are retried on the schedule configured via StreamConfigurationOptions.
+func retryOnRecoverable[T any](options *StreamConfigurationOptions, fn func() (T, error)) (T, error) {
+	maxRetries := uint32(4)
+	if options != nil {
+		maxRetries = options.RecoveryRetries
+	}
+
+	backoff := backoffFor(options)
+
+	var result T
+	var err error
+	for attempt := uint32(0); ; attempt++ {
+		result, err = fn()
+		if err == nil {
+			return result, nil
+		}
+
+		zbErr, ok := err.(*ZerobusError)
+		if ok {
+			observerFor(options).OnStreamError(zbErr)
+		}
+
+		if !ok || !zbErr.IsRetryable || attempt >= maxRetries {
+			return result, err
+		}
+
+		observerFor(options).OnRecovery(int(attempt), err)
+		time.Sleep(backoff.Backoff(int(attempt)))
+	}
+}
@@ -0,0 +1,142 @@
+package zerobus
+
+import (
+	"testing"
+	"time"
+)
+
+// TestBackoffConfigCapsAtMaxDelay verifies the computed delay never exceeds MaxDelay.
+func TestBackoffConfigCapsAtMaxDelay(t *testing.T) {
+	cfg := &BackoffConfig{
+		BaseDelay:  1 * time.Second,
+		MaxDelay:   5 * time.Second,
+		Multiplier: 2,
+		Jitter:     0,
+	}
+
+	for retries := 0; retries < 10; retries++ {
+		if d := cfg.Backoff(retries); d > cfg.MaxDelay {
+			t.Errorf("Backoff(%d) = %v, want <= %v", retries, d, cfg.MaxDelay)
+		}
+	}
+}
+
+// TestBackoffConfigGrowsExponentially verifies the delay increases with retries
+// before hitting the cap.
+func TestBackoffConfigGrowsExponentially(t *testing.T) {
+	cfg := &BackoffConfig{
+		BaseDelay:  1 * time.Second,
+		MaxDelay:   1 * time.Minute,
+		Multiplier: 2,
+		Jitter:     0,
+	}
+
+	prev := cfg.Backoff(0)
+	for retries := 1; retries < 4; retries++ {
+		d := cfg.Backoff(retries)
+		if d <= prev {
+			t.Errorf("Backoff(%d) = %v, want > Backoff(%d) = %v", retries, d, retries-1, prev)
+		}
+		prev = d
+	}
+}
+
+// TestBackoffConfigJitterWithinBounds verifies jitter perturbs the delay by at
+// most the configured fraction in either direction.
+func TestBackoffConfigJitterWithinBounds(t *testing.T) {
+	cfg := &BackoffConfig{
+		BaseDelay:  10 * time.Second,
+		MaxDelay:   10 * time.Second,
+		Multiplier: 1,
+		Jitter:     0.2,
+	}
+
+	lower := time.Duration(float64(cfg.BaseDelay) * 0.8)
+	upper := time.Duration(float64(cfg.BaseDelay) * 1.2)
+
+	for i := 0; i < 100; i++ {
+		d := cfg.Backoff(0)
+		if d < lower || d > upper {
+			t.Fatalf("Backoff(0) = %v, want within [%v, %v]", d, lower, upper)
+		}
+	}
+}
+
+// TestDefaultBackoffConfig verifies the documented defaults.
+func TestDefaultBackoffConfig(t *testing.T) {
+	cfg := DefaultBackoffConfig()
+
+	if cfg.BaseDelay != 1*time.Second {
+		t.Errorf("BaseDelay = %v, want 1s", cfg.BaseDelay)
+	}
+	if cfg.MaxDelay != 120*time.Second {
+		t.Errorf("MaxDelay = %v, want 120s", cfg.MaxDelay)
+	}
+	if cfg.Multiplier != 1.6 {
+		t.Errorf("Multiplier = %v, want 1.6", cfg.Multiplier)
+	}
+	if cfg.Jitter != 0.2 {
+		t.Errorf("Jitter = %v, want 0.2", cfg.Jitter)
+	}
+}
+
+// TestRetryOnRecoverableStopsOnSuccess verifies fn is not retried once it succeeds.
+func TestRetryOnRecoverableStopsOnSuccess(t *testing.T) {
+	calls := 0
+	result, err := retryOnRecoverable(DefaultStreamConfigurationOptions(), func() (int, error) {
+		calls++
+		return 42, nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != 42 {
+		t.Errorf("result = %d, want 42", result)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+// TestRetryOnRecoverableStopsOnNonRetryable verifies non-retryable errors are
+// returned immediately without retrying.
+func TestRetryOnRecoverableStopsOnNonRetryable(t *testing.T) {
+	calls := 0
+	wantErr := &ZerobusError{Message: "fatal", IsRetryable: false}
+
+	_, err := retryOnRecoverable(DefaultStreamConfigurationOptions(), func() (int, error) {
+		calls++
+		return 0, wantErr
+	})
+
+	if err != wantErr {
+		t.Errorf("err = %v, want %v", err, wantErr)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+// TestRetryOnRecoverableExhaustsRetries verifies retries stop after
+// RecoveryRetries attempts and return the last error.
+func TestRetryOnRecoverableExhaustsRetries(t *testing.T) {
+	opts := DefaultStreamConfigurationOptions()
+	opts.RecoveryRetries = 2
+	opts.Backoff = &BackoffConfig{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}
+
+	calls := 0
+	wantErr := &ZerobusError{Message: "transient", IsRetryable: true}
+
+	_, err := retryOnRecoverable(opts, func() (int, error) {
+		calls++
+		return 0, wantErr
+	})
+
+	if err != wantErr {
+		t.Errorf("err = %v, want %v", err, wantErr)
+	}
+	if calls != 3 { // initial attempt + 2 retries
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
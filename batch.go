@@ -0,0 +1,184 @@
+package zerobus
+
+import (
+	"context"
+	"fmt"
+)
+
+// IngestRecords ingests many records in one Go-to-C transition per chunk of
+// each payload type, instead of paying the CGo boundary cost once per record as
+// IngestRecord does. This is the recommended way to ingest at the batch
+// sizes described in the package docs (10k+ records).
+//
+// The payloads slice accepts a mix of []byte (Protocol Buffer) and string
+// (JSON) elements, auto-detected per element exactly like IngestRecord.
+// Records of the same type are sent together in chunks of at most
+// StreamConfigurationOptions.BatchSize; the returned acks are in the same
+// order as payloads regardless of how they were grouped internally.
+//
+// If an error occurs, no RecordAcks are returned for any record in payloads,
+// including ones from a batch that had already been queued; callers that
+// need partial-failure granularity should fall back to IngestRecord. Acks
+// discarded this way still have their inflight/Observer accounting cleaned
+// up (see abandonAcks); only the ack itself, and the server-side
+// acknowledgment it would have delivered, are lost.
+//
+// Callers that already know all of their records share one type should
+// prefer IngestProtoBatch or IngestJSONBatch, which skip the per-element
+// type switch and grouping done here.
+func (st *ZerobusStream) IngestRecords(payloads []interface{}) ([]*RecordAck, error) {
+	if st.ptr == nil {
+		return nil, &ZerobusError{Message: "Stream has been closed", IsRetryable: false, Code: ErrCodeStreamClosed}
+	}
+	if len(payloads) == 0 {
+		return nil, nil
+	}
+
+	var protoIdx []int
+	var protoData [][]byte
+	var jsonIdx []int
+	var jsonData []string
+
+	for i, p := range payloads {
+		switch v := p.(type) {
+		case []byte:
+			protoIdx = append(protoIdx, i)
+			protoData = append(protoData, v)
+		case string:
+			jsonIdx = append(jsonIdx, i)
+			jsonData = append(jsonData, v)
+		default:
+			return nil, &ZerobusError{
+				Message:     fmt.Sprintf("invalid payload type at index %d: must be []byte or string", i),
+				IsRetryable: false,
+			}
+		}
+	}
+
+	acks := make([]*RecordAck, len(payloads))
+
+	if len(protoData) > 0 {
+		protoAcks, err := st.IngestProtoBatch(protoData)
+		if err != nil {
+			return nil, err
+		}
+		for k, ack := range protoAcks {
+			acks[protoIdx[k]] = ack
+		}
+	}
+
+	if len(jsonData) > 0 {
+		jsonAcks, err := st.IngestJSONBatch(jsonData)
+		if err != nil {
+			// protoData, if any, already queued successfully above; don't
+			// strand its inflight/Observer accounting just because the
+			// JSON half of this call failed.
+			abandonAcks(acks, err)
+			return nil, err
+		}
+		for k, ack := range jsonAcks {
+			acks[jsonIdx[k]] = ack
+		}
+	}
+
+	return acks, nil
+}
+
+// IngestRecordsContext is the context-aware variant of IngestRecords.
+// See IngestRecordContext for cancellation semantics.
+func (st *ZerobusStream) IngestRecordsContext(ctx context.Context, payloads []interface{}) ([]*RecordAck, error) {
+	return runWithContext(ctx, func() ([]*RecordAck, error) {
+		return st.IngestRecords(payloads)
+	})
+}
+
+// streamIngestProtoBatchFn and streamIngestJSONBatchFn indirect the FFI
+// calls the batch ingest methods depend on so tests can substitute a fake
+// stream without going through CGo; production code always leaves them
+// pointing at the real functions.
+var (
+	streamIngestProtoBatchFn = streamIngestProtoBatch
+	streamIngestJSONBatchFn  = streamIngestJSONBatch
+)
+
+// IngestProtoBatch ingests many Protocol Buffer records in chunks of at most
+// StreamConfigurationOptions.BatchSize, amortizing the CGo call overhead
+// across each chunk. Returns one RecordAck per input record, in order.
+//
+// If a chunk fails partway through, the RecordAcks already created for
+// earlier, successfully-queued chunks are resolved with that error (via
+// abandonAcks) before returning, so the stream's inflight/Observer
+// accounting doesn't leak just because those acks are discarded along with
+// the error; their eventual server-side acknowledgment, if any, is still
+// lost, same as IngestRecords documents.
+func (st *ZerobusStream) IngestProtoBatch(records [][]byte) ([]*RecordAck, error) {
+	if st.ptr == nil {
+		return nil, &ZerobusError{Message: "Stream has been closed", IsRetryable: false, Code: ErrCodeStreamClosed}
+	}
+
+	batchSize := int(st.batchSize())
+	acks := make([]*RecordAck, len(records))
+
+	for start := 0; start < len(records); start += batchSize {
+		end := min(start+batchSize, len(records))
+		ackIDs, err := streamIngestProtoBatchFn(st.ptr, records[start:end])
+		if err != nil {
+			abandonAcks(acks[:start], err)
+			return nil, err
+		}
+		for k, id := range ackIDs {
+			acks[start+k] = st.newAck(id, len(records[start+k]))
+		}
+	}
+
+	return acks, nil
+}
+
+// IngestJSONBatch ingests many JSON records in chunks of at most
+// StreamConfigurationOptions.BatchSize, amortizing the CGo call overhead
+// across each chunk. Returns one RecordAck per input record, in order.
+//
+// If a chunk fails partway through, the RecordAcks already created for
+// earlier, successfully-queued chunks are resolved with that error (via
+// abandonAcks) before returning, so the stream's inflight/Observer
+// accounting doesn't leak just because those acks are discarded along with
+// the error; their eventual server-side acknowledgment, if any, is still
+// lost, same as IngestRecords documents.
+func (st *ZerobusStream) IngestJSONBatch(records []string) ([]*RecordAck, error) {
+	if st.ptr == nil {
+		return nil, &ZerobusError{Message: "Stream has been closed", IsRetryable: false, Code: ErrCodeStreamClosed}
+	}
+
+	batchSize := int(st.batchSize())
+	acks := make([]*RecordAck, len(records))
+
+	for start := 0; start < len(records); start += batchSize {
+		end := min(start+batchSize, len(records))
+		ackIDs, err := streamIngestJSONBatchFn(st.ptr, records[start:end])
+		if err != nil {
+			abandonAcks(acks[:start], err)
+			return nil, err
+		}
+		for k, id := range ackIDs {
+			acks[start+k] = st.newAck(id, len(records[start+k]))
+		}
+	}
+
+	return acks, nil
+}
+
+// batchSize returns the effective BatchSize for this stream, falling back to
+// the package default when the stream wasn't configured with one.
+func (st *ZerobusStream) batchSize() uint32 {
+	if st.options != nil && st.options.BatchSize > 0 {
+		return st.options.BatchSize
+	}
+	return 1000
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
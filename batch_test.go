@@ -0,0 +1,150 @@
+package zerobus
+
+import (
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// recordingObserver tracks OnRecordQueued/OnRecordAcked calls so tests can
+// assert which ackIDs were queued and which were later resolved (including
+// via abandonAcks), without needing a real Observer adapter.
+type recordingObserver struct {
+	NoopObserver
+	queued []uint64
+	acked  []uint64
+}
+
+func (o *recordingObserver) OnRecordQueued(ackID uint64, size int) {
+	o.queued = append(o.queued, ackID)
+}
+
+func (o *recordingObserver) OnRecordAcked(ackID uint64, offset int64, latency time.Duration) {
+	o.acked = append(o.acked, ackID)
+}
+
+// withFakeBatchIngest replaces streamIngestProtoBatchFn/streamIngestJSONBatchFn
+// for the duration of a test and restores the real FFI-backed functions
+// afterwards.
+func withFakeBatchIngest(t *testing.T, protoFn func(unsafe.Pointer, [][]byte) ([]uint64, error), jsonFn func(unsafe.Pointer, []string) ([]uint64, error)) {
+	t.Helper()
+	origProto, origJSON := streamIngestProtoBatchFn, streamIngestJSONBatchFn
+	if protoFn != nil {
+		streamIngestProtoBatchFn = protoFn
+	}
+	if jsonFn != nil {
+		streamIngestJSONBatchFn = jsonFn
+	}
+	t.Cleanup(func() {
+		streamIngestProtoBatchFn, streamIngestJSONBatchFn = origProto, origJSON
+	})
+}
+
+// TestIngestProtoBatchAbandonsEarlierChunksOnLaterFailure verifies that when
+// a later chunk fails, RecordAcks from earlier, successfully-queued chunks
+// are abandoned (Observer notified, inflight released) rather than leaked,
+// even though none of them are returned to the caller.
+func TestIngestProtoBatchAbandonsEarlierChunksOnLaterFailure(t *testing.T) {
+	observer := &recordingObserver{}
+	st := &ZerobusStream{
+		ptr:     fakePtr,
+		options: &StreamConfigurationOptions{BatchSize: 2, Observer: observer},
+	}
+
+	calls := 0
+	wantErr := &ZerobusError{Message: "boom", Code: ErrCodeInternal}
+	withFakeBatchIngest(t, func(unsafe.Pointer, [][]byte) ([]uint64, error) {
+		calls++
+		if calls == 1 {
+			return []uint64{1, 2}, nil
+		}
+		return nil, wantErr
+	}, nil)
+
+	acks, err := st.IngestProtoBatch([][]byte{{1}, {2}, {3}, {4}})
+	if err != wantErr {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if acks != nil {
+		t.Fatalf("acks = %v, want nil", acks)
+	}
+
+	if calls != 2 {
+		t.Fatalf("streamIngestProtoBatchFn called %d times, want 2", calls)
+	}
+	if got := observer.queued; len(got) != 2 {
+		t.Fatalf("queued = %v, want 2 ackIDs queued from the first chunk", got)
+	}
+	if got := observer.acked; len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Fatalf("acked = %v, want [1 2] (both abandoned with the later chunk's error)", got)
+	}
+	if got := st.inflight; got != 0 {
+		t.Errorf("inflight = %d, want 0 after abandonment", got)
+	}
+}
+
+// TestIngestJSONBatchAbandonsEarlierChunksOnLaterFailure is the JSON
+// counterpart of TestIngestProtoBatchAbandonsEarlierChunksOnLaterFailure.
+func TestIngestJSONBatchAbandonsEarlierChunksOnLaterFailure(t *testing.T) {
+	observer := &recordingObserver{}
+	st := &ZerobusStream{
+		ptr:     fakePtr,
+		options: &StreamConfigurationOptions{BatchSize: 1, Observer: observer},
+	}
+
+	calls := 0
+	wantErr := &ZerobusError{Message: "boom", Code: ErrCodeInternal}
+	withFakeBatchIngest(t, nil, func(unsafe.Pointer, []string) ([]uint64, error) {
+		calls++
+		if calls == 1 {
+			return []uint64{7}, nil
+		}
+		return nil, wantErr
+	})
+
+	acks, err := st.IngestJSONBatch([]string{"a", "b"})
+	if err != wantErr {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if acks != nil {
+		t.Fatalf("acks = %v, want nil", acks)
+	}
+
+	if got := observer.acked; len(got) != 1 || got[0] != 7 {
+		t.Fatalf("acked = %v, want [7] (the one ack from the successful first chunk)", got)
+	}
+	if got := st.inflight; got != 0 {
+		t.Errorf("inflight = %d, want 0 after abandonment", got)
+	}
+}
+
+// TestIngestRecordsAbandonsProtoAcksWhenJSONHalfFails verifies that a
+// failure in the JSON half of IngestRecords abandons the RecordAcks already
+// queued by the proto half instead of leaking their inflight accounting.
+func TestIngestRecordsAbandonsProtoAcksWhenJSONHalfFails(t *testing.T) {
+	observer := &recordingObserver{}
+	st := &ZerobusStream{
+		ptr:     fakePtr,
+		options: &StreamConfigurationOptions{BatchSize: 10, Observer: observer},
+	}
+
+	wantErr := &ZerobusError{Message: "boom", Code: ErrCodeInternal}
+	withFakeBatchIngest(t,
+		func(unsafe.Pointer, [][]byte) ([]uint64, error) { return []uint64{1}, nil },
+		func(unsafe.Pointer, []string) ([]uint64, error) { return nil, wantErr },
+	)
+
+	acks, err := st.IngestRecords([]interface{}{[]byte{1}, "json-record"})
+	if err != wantErr {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if acks != nil {
+		t.Fatalf("acks = %v, want nil", acks)
+	}
+	if got := observer.acked; len(got) != 1 || got[0] != 1 {
+		t.Fatalf("acked = %v, want [1] (the proto ack abandoned alongside the JSON error)", got)
+	}
+	if got := st.inflight; got != 0 {
+		t.Errorf("inflight = %d, want 0 after abandonment", got)
+	}
+}
@@ -0,0 +1,295 @@
+package zerobus
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// runWithContext runs fn on a background goroutine and returns as soon as
+// either fn completes or ctx is done, whichever happens first.
+//
+// fn wraps a blocking CGo call that cannot itself be interrupted, so on
+// cancellation this returns ctx.Err() immediately while fn keeps running in
+// the background until the underlying FFI call returns; the SDK call it
+// wraps may still complete (and, for ingestion, still be durably persisted
+// server-side) after the caller has already moved on.
+func runWithContext[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	result := make(chan struct {
+		val T
+		err error
+	}, 1)
+
+	go func() {
+		val, err := fn()
+		result <- struct {
+			val T
+			err error
+		}{val, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case r := <-result:
+		return r.val, r.err
+	}
+}
+
+// NewZerobusSdkContext is the context-aware variant of NewZerobusSdk.
+// If ctx is canceled or its deadline elapses before the SDK is created,
+// this returns ctx.Err(); the underlying creation call may still complete
+// in the background, in which case the resulting SDK is freed once done.
+func NewZerobusSdkContext(ctx context.Context, zerobusEndpoint, unityCatalogURL string) (*ZerobusSdk, error) {
+	created := make(chan *ZerobusSdk, 1)
+	sdk, err := runWithContext(ctx, func() (*ZerobusSdk, error) {
+		sdk, err := NewZerobusSdk(zerobusEndpoint, unityCatalogURL)
+		created <- sdk
+		return sdk, err
+	})
+	if err != nil && ctx.Err() != nil {
+		go func() {
+			if sdk := <-created; sdk != nil {
+				sdk.Free()
+			}
+		}()
+	}
+	return sdk, err
+}
+
+// CreateStreamContext is the context-aware variant of CreateStream.
+// If ctx is canceled or its deadline elapses before the stream is created,
+// this returns ctx.Err(); the underlying creation call (including its
+// configured recovery retries) may still complete in the background, in
+// which case the resulting stream is closed once done.
+func (s *ZerobusSdk) CreateStreamContext(
+	ctx context.Context,
+	tableProps TableProperties,
+	clientID string,
+	clientSecret string,
+	options *StreamConfigurationOptions,
+) (*ZerobusStream, error) {
+	created := make(chan *ZerobusStream, 1)
+	stream, err := runWithContext(ctx, func() (*ZerobusStream, error) {
+		stream, err := s.CreateStream(tableProps, clientID, clientSecret, options)
+		created <- stream
+		return stream, err
+	})
+	if err != nil && ctx.Err() != nil {
+		go func() {
+			if stream := <-created; stream != nil {
+				stream.Close()
+			}
+		}()
+	}
+	return stream, err
+}
+
+// CreateStreamWithHeadersProviderContext is the context-aware variant of
+// CreateStreamWithHeadersProvider. See CreateStreamContext for cancellation
+// semantics.
+func (s *ZerobusSdk) CreateStreamWithHeadersProviderContext(
+	ctx context.Context,
+	tableProps TableProperties,
+	headersProvider HeadersProvider,
+	options *StreamConfigurationOptions,
+) (*ZerobusStream, error) {
+	created := make(chan *ZerobusStream, 1)
+	stream, err := runWithContext(ctx, func() (*ZerobusStream, error) {
+		stream, err := s.CreateStreamWithHeadersProvider(tableProps, headersProvider, options)
+		created <- stream
+		return stream, err
+	})
+	if err != nil && ctx.Err() != nil {
+		go func() {
+			if stream := <-created; stream != nil {
+				stream.Close()
+			}
+		}()
+	}
+	return stream, err
+}
+
+// IngestRecordContext is the context-aware variant of IngestRecord.
+// If ctx is canceled or its deadline elapses first, this returns ctx.Err();
+// the record may still be queued (and later acknowledged) in the background,
+// but the caller has no way to retrieve the resulting RecordAck in that case.
+func (st *ZerobusStream) IngestRecordContext(ctx context.Context, payload interface{}) (*RecordAck, error) {
+	return runWithContext(ctx, func() (*RecordAck, error) {
+		return st.IngestRecord(payload)
+	})
+}
+
+// FlushContext is the context-aware variant of Flush. Unlike the other
+// Context variants, it doesn't merely abandon a background goroutine: the
+// Rust side polls ctx for cancellation directly, so when ctx is canceled or
+// its deadline elapses the in-progress flush aborts cooperatively and
+// returns ctx.Err() instead of running to completion unobserved.
+// Already-queued records are not discarded; they're still flushed on a
+// subsequent call.
+func (st *ZerobusStream) FlushContext(ctx context.Context) error {
+	if st.ptr == nil {
+		return &ZerobusError{Message: "Stream has been closed", IsRetryable: false, Code: ErrCodeStreamClosed}
+	}
+
+	pending := int(atomic.LoadInt64(&st.inflight))
+	start := time.Now()
+	err := streamFlushCancellable(st.ptr, ctx)
+	if err != nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	st.observer().OnFlush(pending, time.Since(start), err)
+	return err
+}
+
+// CloseContext is the context-aware variant of Close.
+// Unlike the other Context variants, cancellation here does not leave the
+// blocking call to finish on its own: if ctx is done before Close returns,
+// CloseContext forces the underlying stream closed immediately (skipping
+// the final flush) so the stream is guaranteed unusable once this returns.
+//
+// This races claimPtrForClose against the background Close() call rather
+// than waiting on it: if CloseContext wins, it frees the stream directly
+// without the (potentially minutes-long) flush-then-close that Close()
+// performs; if Close() already won, the stream is already unusable (st.ptr
+// is nil) and the in-progress close simply finishes on its own.
+func (st *ZerobusStream) CloseContext(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- st.Close()
+	}()
+
+	select {
+	case <-ctx.Done():
+		if ptr, ok := st.claimPtrForClose(); ok {
+			streamFreeFn(ptr)
+		}
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
+
+// AwaitContext is the context-aware variant of Await. Instead of blocking on
+// the FFI's own wait call, it polls TryGet with a small increasing interval
+// so it can observe ctx cancellation promptly. If ctx is canceled or its
+// deadline elapses before the acknowledgment arrives, this returns ctx.Err();
+// the record's eventual ack, if any, is not lost: nothing is cached on that
+// path, so a later Await or AwaitContext call can still retrieve it.
+//
+// Like Await, a retryable *ZerobusError (e.g. ErrCodeBackpressure,
+// ErrCodeTransport) observed from TryGet is retried on the stream's
+// configured backoff schedule (StreamConfigurationOptions.RecoveryRetries and
+// Backoff, or RecoveryBackoffMs when Backoff is nil) instead of being
+// returned immediately; unlike Await's
+// retryOnRecoverable, each retry's backoff wait is itself ctx-aware, so
+// cancellation is never blocked on it.
+//
+// A successful result is cached through the same a.once that Await() uses,
+// so a later call to Await() (directly, or via WaitAll/WaitAny/Done) returns
+// the cached offset/err instead of re-dispatching streamAwaitAck for an
+// already-delivered ack.
+func (a *RecordAck) AwaitContext(ctx context.Context) (int64, error) {
+	const minPollInterval = 5 * time.Millisecond
+	const maxPollInterval = 100 * time.Millisecond
+
+	maxRetries := uint32(4)
+	if a.options != nil {
+		maxRetries = a.options.RecoveryRetries
+	}
+	backoff := backoffFor(a.options)
+
+	interval := minPollInterval
+	retryAttempt := uint32(0)
+	for {
+		if offset, err, ready := a.TryGet(); ready {
+			if zbErr, ok := err.(*ZerobusError); ok && zbErr.IsRetryable && retryAttempt < maxRetries {
+				observerFor(a.options).OnStreamError(zbErr)
+				observerFor(a.options).OnRecovery(int(retryAttempt), err)
+
+				select {
+				case <-ctx.Done():
+					return 0, ctx.Err()
+				case <-time.After(backoff.Backoff(int(retryAttempt))):
+				}
+				retryAttempt++
+				continue
+			}
+
+			a.once.Do(func() {
+				a.offset, a.err = offset, err
+				a.resolved(offset, err)
+			})
+			return a.offset, a.err
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		if interval *= 2; interval > maxPollInterval {
+			interval = maxPollInterval
+		}
+	}
+}
+
+// WaitAll waits for every ack in acks to be acknowledged and returns their
+// offsets in the same order, or ctx.Err() if ctx is canceled or its deadline
+// elapses first. Acks are waited on in order; the first one found to have
+// resolved with an error ends the wait, and WaitAll returns that error
+// without waiting on the acks after it.
+func WaitAll(ctx context.Context, acks []*RecordAck) ([]int64, error) {
+	offsets := make([]int64, len(acks))
+	for i, ack := range acks {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ack.Done():
+		}
+
+		offset, err := ack.Await()
+		if err != nil {
+			return nil, err
+		}
+		offsets[i] = offset
+	}
+	return offsets, nil
+}
+
+// WaitAny waits for the first ack in acks to be acknowledged and returns its
+// index, offset, and error, or (0, 0, ctx.Err()) if ctx is canceled or its
+// deadline elapses before any of them resolve.
+func WaitAny(ctx context.Context, acks []*RecordAck) (int, int64, error) {
+	type result struct {
+		index  int
+		offset int64
+		err    error
+	}
+
+	results := make(chan result, len(acks))
+	for i, ack := range acks {
+		i, ack := i, ack
+		go func() {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ack.Done():
+			}
+			offset, err := ack.Await()
+			select {
+			case results <- result{i, offset, err}:
+			default:
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		return 0, 0, ctx.Err()
+	case r := <-results:
+		return r.index, r.offset, r.err
+	}
+}
@@ -0,0 +1,253 @@
+package zerobus
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// fakePtr is a non-nil, never-dereferenced stand-in for a CZerobusStream
+// pointer, safe to pass to streamCloseFn/streamFreeFn once those are faked.
+var fakePtr = unsafe.Pointer(&struct{}{})
+
+// withFakeStreamClose replaces streamCloseFn/streamFreeFn for the duration
+// of a test and restores the real FFI-backed functions afterwards.
+func withFakeStreamClose(t *testing.T, closeFn func(unsafe.Pointer) error, freeFn func(unsafe.Pointer)) {
+	t.Helper()
+	origClose, origFree := streamCloseFn, streamFreeFn
+	streamCloseFn, streamFreeFn = closeFn, freeFn
+	t.Cleanup(func() {
+		streamCloseFn, streamFreeFn = origClose, origFree
+	})
+}
+
+// TestCloseContextForcesCloseWithoutWaiting verifies that when ctx is
+// canceled while a slow Close() is still running in the background,
+// CloseContext returns immediately instead of waiting for that Close() to
+// finish its flush-then-close.
+func TestCloseContextForcesCloseWithoutWaiting(t *testing.T) {
+	closeStarted := make(chan struct{})
+	releaseClose := make(chan struct{})
+	var freeCalls int32
+
+	withFakeStreamClose(t,
+		func(unsafe.Pointer) error {
+			close(closeStarted)
+			<-releaseClose
+			return nil
+		},
+		func(unsafe.Pointer) { atomic.AddInt32(&freeCalls, 1) },
+	)
+	defer close(releaseClose)
+
+	st := &ZerobusStream{ptr: fakePtr}
+
+	closeDone := make(chan error, 1)
+	go func() { closeDone <- st.Close() }()
+	<-closeStarted // Close() is now blocked inside streamCloseFn.
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := st.CloseContext(ctx)
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("CloseContext took %v, want near-instant return on an already-canceled ctx", elapsed)
+	}
+	if err != context.Canceled {
+		t.Errorf("CloseContext err = %v, want context.Canceled", err)
+	}
+	if st.ptr != nil {
+		t.Error("st.ptr != nil after CloseContext; stream should be unusable immediately")
+	}
+
+	releaseClose <- struct{}{}
+	if err := <-closeDone; err != nil {
+		t.Errorf("background Close() err = %v, want nil", err)
+	}
+
+	if got := atomic.LoadInt32(&freeCalls); got != 1 {
+		t.Errorf("streamFreeFn called %d times, want exactly 1 (no double free)", got)
+	}
+}
+
+// TestCloseContextNoDoubleFreeOnAlreadyCanceledCtx exercises CloseContext
+// with an already-canceled ctx, so its own internal Close() goroutine and
+// its ctx.Done() force-close branch genuinely race to claim st.ptr. Either
+// outcome is valid (claimPtrForClose guarantees only one of them does), but
+// regardless of who wins, the stream must end up freed exactly once and
+// CloseContext must return ctx.Err() promptly.
+func TestCloseContextNoDoubleFreeOnAlreadyCanceledCtx(t *testing.T) {
+	var closeCalls, freeCalls int32
+	withFakeStreamClose(t,
+		func(unsafe.Pointer) error { atomic.AddInt32(&closeCalls, 1); return nil },
+		func(unsafe.Pointer) { atomic.AddInt32(&freeCalls, 1) },
+	)
+
+	st := &ZerobusStream{ptr: fakePtr}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := st.CloseContext(ctx)
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("CloseContext took %v, want near-instant return on an already-canceled ctx", elapsed)
+	}
+	if err != context.Canceled {
+		t.Errorf("CloseContext err = %v, want context.Canceled", err)
+	}
+
+	// Close() runs its FFI call in a goroutine that may still be in flight
+	// when CloseContext returns (the forced-close path doesn't wait for
+	// it); give it a moment to finish before inspecting the call counts.
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&freeCalls) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if got := atomic.LoadInt32(&freeCalls); got != 1 {
+		t.Errorf("streamFreeFn called %d times, want exactly 1 (no double free)", got)
+	}
+	if got := atomic.LoadInt32(&closeCalls); got > 1 {
+		t.Errorf("streamCloseFn called %d times, want at most 1", got)
+	}
+}
+
+// TestClaimPtrForCloseIsExclusive verifies claimPtrForClose hands out the
+// real pointer to exactly one of many concurrent callers.
+func TestClaimPtrForCloseIsExclusive(t *testing.T) {
+	st := &ZerobusStream{ptr: fakePtr}
+
+	const callers = 20
+	var wins int32
+	done := make(chan struct{}, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			if _, ok := st.claimPtrForClose(); ok {
+				atomic.AddInt32(&wins, 1)
+			}
+			done <- struct{}{}
+		}()
+	}
+	for i := 0; i < callers; i++ {
+		<-done
+	}
+
+	if wins != 1 {
+		t.Errorf("claimPtrForClose succeeded %d times, want exactly 1", wins)
+	}
+}
+
+// withFakeAck replaces streamAwaitAckFn/streamTryGetAckFn for the duration
+// of a test and restores the real FFI-backed functions afterwards.
+func withFakeAck(t *testing.T, awaitFn func(uint64) (int64, error), tryGetFn func(uint64) (int64, error, bool)) {
+	t.Helper()
+	origAwait, origTryGet := streamAwaitAckFn, streamTryGetAckFn
+	streamAwaitAckFn, streamTryGetAckFn = awaitFn, tryGetFn
+	t.Cleanup(func() {
+		streamAwaitAckFn, streamTryGetAckFn = origAwait, origTryGet
+	})
+}
+
+// TestAwaitContextCachesResultForSubsequentAwait verifies that once
+// AwaitContext observes a ready ack, a later call to Await() returns the
+// cached result instead of re-dispatching streamAwaitAck.
+func TestAwaitContextCachesResultForSubsequentAwait(t *testing.T) {
+	var awaitCalls int32
+	withFakeAck(t,
+		func(uint64) (int64, error) {
+			atomic.AddInt32(&awaitCalls, 1)
+			return 42, nil
+		},
+		func(uint64) (int64, error, bool) { return 42, nil, true },
+	)
+
+	ack := &RecordAck{ackID: 1}
+
+	offset, err := ack.AwaitContext(context.Background())
+	if err != nil || offset != 42 {
+		t.Fatalf("AwaitContext() = (%d, %v), want (42, nil)", offset, err)
+	}
+
+	offset, err = ack.Await()
+	if err != nil || offset != 42 {
+		t.Fatalf("Await() after AwaitContext = (%d, %v), want (42, nil)", offset, err)
+	}
+	if got := atomic.LoadInt32(&awaitCalls); got != 0 {
+		t.Errorf("streamAwaitAckFn called %d times after AwaitContext resolved, want 0", got)
+	}
+}
+
+// TestAwaitContextReturnsCtxErrOnCancellation verifies AwaitContext gives up
+// promptly when ctx is canceled before the ack becomes ready.
+func TestAwaitContextReturnsCtxErrOnCancellation(t *testing.T) {
+	withFakeAck(t,
+		func(uint64) (int64, error) { return 0, nil },
+		func(uint64) (int64, error, bool) { return 0, nil, false },
+	)
+
+	ack := &RecordAck{ackID: 2}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := ack.AwaitContext(ctx); err != context.Canceled {
+		t.Errorf("AwaitContext err = %v, want context.Canceled", err)
+	}
+}
+
+// TestAwaitContextRetriesRetryableErrorLikeAwait verifies that a retryable
+// *ZerobusError reported by TryGet is retried, the same way Await retries it
+// via retryOnRecoverable, instead of being returned to the caller on the
+// first "ready" result.
+func TestAwaitContextRetriesRetryableErrorLikeAwait(t *testing.T) {
+	var tryGetCalls int32
+	withFakeAck(t,
+		func(uint64) (int64, error) { return 0, nil },
+		func(uint64) (int64, error, bool) {
+			n := atomic.AddInt32(&tryGetCalls, 1)
+			if n < 3 {
+				return 0, ErrBackpressure, true
+			}
+			return 99, nil, true
+		},
+	)
+
+	ack := &RecordAck{ackID: 3, options: &StreamConfigurationOptions{RecoveryRetries: 2, RecoveryBackoffMs: 1}}
+
+	offset, err := ack.AwaitContext(context.Background())
+	if err != nil || offset != 99 {
+		t.Fatalf("AwaitContext() = (%d, %v), want (99, nil)", offset, err)
+	}
+	if got := atomic.LoadInt32(&tryGetCalls); got != 3 {
+		t.Errorf("streamTryGetAckFn called %d times, want 3 (2 retried + 1 success)", got)
+	}
+}
+
+// TestAwaitContextGivesUpAfterRecoveryRetriesExhausted verifies AwaitContext
+// stops retrying a persistently retryable error once
+// StreamConfigurationOptions.RecoveryRetries attempts have been made, and
+// returns that error instead of retrying forever.
+func TestAwaitContextGivesUpAfterRecoveryRetriesExhausted(t *testing.T) {
+	var tryGetCalls int32
+	withFakeAck(t,
+		func(uint64) (int64, error) { return 0, nil },
+		func(uint64) (int64, error, bool) {
+			atomic.AddInt32(&tryGetCalls, 1)
+			return 0, ErrBackpressure, true
+		},
+	)
+
+	ack := &RecordAck{ackID: 4, options: &StreamConfigurationOptions{RecoveryRetries: 2, RecoveryBackoffMs: 1}}
+
+	_, err := ack.AwaitContext(context.Background())
+	if err != ErrBackpressure {
+		t.Fatalf("AwaitContext() err = %v, want ErrBackpressure", err)
+	}
+	if got := atomic.LoadInt32(&tryGetCalls); got != 3 {
+		t.Errorf("streamTryGetAckFn called %d times, want 3 (1 initial + 2 retries)", got)
+	}
+}
@@ -0,0 +1,113 @@
+// Package zerobusotel adapts zerobus.Observer to OpenTelemetry tracing and
+// metrics.
+//
+// It has its own go.mod (see go.work at the repo root for local development
+// across modules) so that the root zerobus package doesn't force an
+// OpenTelemetry dependency, or OpenTelemetry's own Go version floor, on
+// users who don't already use it; import this package only if your
+// application depends on go.opentelemetry.io/otel. This module currently
+// requires Go 1.25+, newer than the root module's Go 1.19+.
+package zerobusotel
+
+import (
+	"context"
+	"time"
+
+	"github.com/databricks/zerobus-sdk-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Observer is a zerobus.Observer that records ingest/ack activity as
+// OpenTelemetry metrics (in-flight count, ack latency histogram, retry
+// counter) and opens a span per record that is ended, by ackID, when
+// OnRecordAcked reports that record's acknowledgment.
+type Observer struct {
+	tracer trace.Tracer
+
+	inflight   metric.Int64UpDownCounter
+	ackLatency metric.Float64Histogram
+	recoveries metric.Int64Counter
+	errors     metric.Int64Counter
+
+	spans spanMap
+}
+
+// NewObserver creates an Observer whose tracer and meter are obtained from
+// the global OpenTelemetry providers under instrumentation name
+// "github.com/databricks/zerobus-sdk-go".
+func NewObserver() (*Observer, error) {
+	const instrumentationName = "github.com/databricks/zerobus-sdk-go"
+	meter := otel.Meter(instrumentationName)
+
+	inflight, err := meter.Int64UpDownCounter("zerobus.inflight_records",
+		metric.WithDescription("Number of records queued but not yet acknowledged."))
+	if err != nil {
+		return nil, err
+	}
+	ackLatency, err := meter.Float64Histogram("zerobus.ack_latency_seconds",
+		metric.WithDescription("Time between a record being queued and its acknowledgment being observed."))
+	if err != nil {
+		return nil, err
+	}
+	recoveries, err := meter.Int64Counter("zerobus.recoveries_total",
+		metric.WithDescription("Total number of Go-side retries performed after a retryable error."))
+	if err != nil {
+		return nil, err
+	}
+	errors, err := meter.Int64Counter("zerobus.errors_total",
+		metric.WithDescription("Total number of errors reported via Observer.OnStreamError."))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Observer{
+		tracer:     otel.Tracer(instrumentationName),
+		inflight:   inflight,
+		ackLatency: ackLatency,
+		recoveries: recoveries,
+		errors:     errors,
+		spans:      newSpanMap(),
+	}, nil
+}
+
+func (o *Observer) OnRecordQueued(ackID uint64, size int) {
+	ctx := context.Background()
+	_, span := o.tracer.Start(ctx, "zerobus.ingest",
+		trace.WithAttributes(attribute.Int("zerobus.record_size", size)))
+	o.spans.store(ackID, span)
+	o.inflight.Add(ctx, 1)
+}
+
+func (o *Observer) OnRecordAcked(ackID uint64, offset int64, latency time.Duration) {
+	ctx := context.Background()
+	o.inflight.Add(ctx, -1)
+	o.ackLatency.Record(ctx, latency.Seconds())
+
+	if span, ok := o.spans.take(ackID); ok {
+		span.SetAttributes(attribute.Int64("zerobus.offset", offset))
+		span.End()
+	}
+}
+
+func (o *Observer) OnFlush(pending int, duration time.Duration, err error) {
+	ctx := context.Background()
+	_, span := o.tracer.Start(ctx, "zerobus.flush",
+		trace.WithAttributes(attribute.Int("zerobus.pending", pending)))
+	defer span.End()
+	if err != nil {
+		span.RecordError(err)
+	}
+}
+
+func (o *Observer) OnRecovery(attempt int, err error) {
+	o.recoveries.Add(context.Background(), 1)
+}
+
+func (o *Observer) OnStreamError(err *zerobus.ZerobusError) {
+	o.errors.Add(context.Background(), 1)
+}
+
+var _ zerobus.Observer = (*Observer)(nil)
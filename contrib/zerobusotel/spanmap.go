@@ -0,0 +1,36 @@
+package zerobusotel
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanMap matches each OnRecordQueued span to the OnRecordAcked call that
+// ends it, keyed by ackID so spans are ended by the exact record they were
+// opened for even when a stream's acks are observed out of ingest order,
+// e.g. via concurrent RecordAck.Await/AwaitContext/WaitAny callers.
+type spanMap struct {
+	mu    sync.Mutex
+	spans map[uint64]trace.Span
+}
+
+func newSpanMap() spanMap {
+	return spanMap{spans: make(map[uint64]trace.Span)}
+}
+
+func (m *spanMap) store(ackID uint64, span trace.Span) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.spans[ackID] = span
+}
+
+func (m *spanMap) take(ackID uint64) (trace.Span, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	span, ok := m.spans[ackID]
+	if ok {
+		delete(m.spans, ackID)
+	}
+	return span, ok
+}
@@ -0,0 +1,113 @@
+// Package zerobusprom adapts zerobus.Observer to Prometheus metrics.
+//
+// It has its own go.mod (see go.work at the repo root for local development
+// across modules) so that the root zerobus package doesn't force a
+// github.com/prometheus/client_golang dependency on users who don't want
+// it; import this package only if you already depend on
+// client_golang/prometheus.
+package zerobusprom
+
+import (
+	"time"
+
+	"github.com/databricks/zerobus-sdk-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Observer is a zerobus.Observer that reports stream activity as Prometheus
+// metrics. It also implements prometheus.Collector so it can be registered
+// directly with a prometheus.Registerer.
+type Observer struct {
+	inflight    prometheus.Gauge
+	queued      prometheus.Counter
+	acked       prometheus.Counter
+	ackLatency  prometheus.Histogram
+	flushes     prometheus.Counter
+	flushErrors prometheus.Counter
+	recoveries  prometheus.Counter
+	errors      prometheus.Counter
+}
+
+// NewObserver creates an Observer whose metrics are prefixed with namespace
+// (e.g. "myapp") and subsystem (e.g. "zerobus"). Register the returned
+// Observer with a prometheus.Registerer before use.
+func NewObserver(namespace, subsystem string) *Observer {
+	return &Observer{
+		inflight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: subsystem, Name: "inflight_records",
+			Help: "Number of records queued but not yet acknowledged.",
+		}),
+		queued: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem, Name: "records_queued_total",
+			Help: "Total number of records queued for ingestion.",
+		}),
+		acked: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem, Name: "records_acked_total",
+			Help: "Total number of records acknowledged by the server.",
+		}),
+		ackLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: subsystem, Name: "ack_latency_seconds",
+			Help:    "Time between a record being queued and its acknowledgment being observed.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		flushes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem, Name: "flushes_total",
+			Help: "Total number of Flush/FlushContext calls.",
+		}),
+		flushErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem, Name: "flush_errors_total",
+			Help: "Total number of Flush/FlushContext calls that returned an error.",
+		}),
+		recoveries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem, Name: "recoveries_total",
+			Help: "Total number of Go-side retries performed after a retryable error.",
+		}),
+		errors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem, Name: "errors_total",
+			Help: "Total number of errors reported via Observer.OnStreamError.",
+		}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (o *Observer) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(o, ch)
+}
+
+// Collect implements prometheus.Collector.
+func (o *Observer) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range []prometheus.Collector{
+		o.inflight, o.queued, o.acked, o.ackLatency, o.flushes, o.flushErrors, o.recoveries, o.errors,
+	} {
+		c.Collect(ch)
+	}
+}
+
+func (o *Observer) OnRecordQueued(ackID uint64, size int) {
+	o.inflight.Inc()
+	o.queued.Inc()
+}
+
+func (o *Observer) OnRecordAcked(ackID uint64, offset int64, latency time.Duration) {
+	o.inflight.Dec()
+	o.acked.Inc()
+	o.ackLatency.Observe(latency.Seconds())
+}
+
+func (o *Observer) OnFlush(pending int, duration time.Duration, err error) {
+	o.flushes.Inc()
+	if err != nil {
+		o.flushErrors.Inc()
+	}
+}
+
+func (o *Observer) OnRecovery(attempt int, err error) {
+	o.recoveries.Inc()
+}
+
+func (o *Observer) OnStreamError(err *zerobus.ZerobusError) {
+	o.errors.Inc()
+}
+
+var _ zerobus.Observer = (*Observer)(nil)
+var _ prometheus.Collector = (*Observer)(nil)
@@ -0,0 +1,98 @@
+//go:build zerobus_embed
+
+package zerobus
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// This file lets an SDK distributor ship a single Go module that links on
+// every supported platform without requiring end users to have Rust, a C
+// cross-compiler, or network access at build time. It is only compiled in
+// under the zerobus_embed build tag, which a distributor opts into after
+// populating prebuilt/<triple>/libzerobus_ffi.a for every target via
+// `ZEROBUS_FFI_TARGETS=... go generate` (see buildRustLibraryForTargets in
+// internal/ffibuild/build.go).
+//
+// Because cgo resolves #cgo LDFLAGS and links libzerobus_ffi.a before any Go
+// init() runs, the extracted artifact must already be in place the *first*
+// time a fresh checkout is built with this tag — init() alone can't
+// bootstrap that, since it doesn't run until after the link step it would
+// need to precede. The go:generate directive below covers that: it shells
+// out to extract_embed.sh, a cgo-free script that copies the matching
+// prebuilt/<triple>/libzerobus_ffi.a to lib/<goos>_<goarch>/libzerobus_ffi.a
+// without needing the library it's extracting to already be linkable.
+// Run `go generate -tags zerobus_embed .` once per fresh checkout (or
+// whenever vendoring a new SDK version) before `go build -tags
+// zerobus_embed`; init() below then re-extracts each time a program built
+// with the tag starts, so the on-disk artifact that the next build links
+// against never drifts from what's embedded.
+
+//go:generate bash extract_embed.sh
+
+//go:embed prebuilt/*/libzerobus_ffi.a
+var embeddedLibraries embed.FS
+
+// rustTriples maps a "<GOOS>_<GOARCH>" target (with a "_musl" suffix on musl
+// Linux, as computed in extractEmbeddedLibrary) to the Rust target triple used for the directory name under prebuilt/.
+var rustTriples = map[string]string{
+	"linux_amd64":      "x86_64-unknown-linux-gnu",
+	"linux_arm64":      "aarch64-unknown-linux-gnu",
+	"linux_amd64_musl": "x86_64-unknown-linux-musl",
+	"linux_arm64_musl": "aarch64-unknown-linux-musl",
+	"darwin_amd64":     "x86_64-apple-darwin",
+	"darwin_arm64":     "aarch64-apple-darwin",
+	"windows_amd64":    "x86_64-pc-windows-gnu",
+}
+
+func init() {
+	if err := extractEmbeddedLibrary(); err != nil {
+		fmt.Fprintf(os.Stderr, "zerobus: failed to extract embedded FFI library: %v\n", err)
+	}
+}
+
+// extractEmbeddedLibrary writes the libzerobus_ffi.a embedded for the
+// running GOOS/GOARCH/libc to the same lib/<goos>_<goarch>/libzerobus_ffi.a
+// path that ffi.go's #cgo LDFLAGS directives reference, so a subsequent
+// `go build -tags zerobus_embed` links against the version
+// vendored in this module rather than whatever was built or downloaded
+// before.
+func extractEmbeddedLibrary() error {
+	target := fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "linux" {
+		// Same "ldd --version" musl sniff as extract_embed.sh.
+		out, _ := exec.Command("ldd", "--version").CombinedOutput()
+		if strings.Contains(strings.ToLower(string(out)), "musl") {
+			target += "_musl"
+		}
+	}
+
+	triple, ok := rustTriples[target]
+	if !ok {
+		return fmt.Errorf("no embedded library for target %q", target)
+	}
+
+	data, err := embeddedLibraries.ReadFile(path.Join("prebuilt", triple, "libzerobus_ffi.a"))
+	if err != nil {
+		return fmt.Errorf("reading embedded library for %s: %w", triple, err)
+	}
+
+	_, filename, _, ok := runtime.Caller(0)
+	if !ok {
+		return fmt.Errorf("failed to determine source directory")
+	}
+	sdkDir := filepath.Dir(filename)
+	libPath := filepath.Join(sdkDir, "lib", fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH), "libzerobus_ffi.a")
+
+	if err := os.MkdirAll(filepath.Dir(libPath), 0755); err != nil {
+		return fmt.Errorf("failed to create library directory: %w", err)
+	}
+	return os.WriteFile(libPath, data, 0644)
+}
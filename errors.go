@@ -1,21 +1,125 @@
 package zerobus
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
-// ZerobusError represents an error from the Zerobus SDK
+// ErrCode categorizes a ZerobusError so callers can branch on the failure
+// kind instead of string-matching Message, e.g. triggering a token refresh
+// on ErrCodeAuth or applying backoff on ErrCodeBackpressure.
+type ErrCode int32
+
+const (
+	// ErrCodeUnspecified is the zero value, used for errors that predate
+	// error codes (e.g. constructed directly by Go code rather than
+	// surfaced from the FFI layer) or whose code wasn't recognized.
+	ErrCodeUnspecified    ErrCode = 0
+	ErrCodeAuth           ErrCode = 1
+	ErrCodeSchemaMismatch ErrCode = 2
+	ErrCodeStreamClosed   ErrCode = 3
+	ErrCodeBackpressure   ErrCode = 4
+	ErrCodeQuotaExceeded  ErrCode = 5
+	ErrCodeTransport      ErrCode = 6
+	ErrCodeInternal       ErrCode = 7
+)
+
+// String returns the ErrCode's constant name, e.g. "ErrCodeAuth", or
+// "ErrCode(<n>)" for an unrecognized value.
+func (c ErrCode) String() string {
+	switch c {
+	case ErrCodeUnspecified:
+		return "ErrCodeUnspecified"
+	case ErrCodeAuth:
+		return "ErrCodeAuth"
+	case ErrCodeSchemaMismatch:
+		return "ErrCodeSchemaMismatch"
+	case ErrCodeStreamClosed:
+		return "ErrCodeStreamClosed"
+	case ErrCodeBackpressure:
+		return "ErrCodeBackpressure"
+	case ErrCodeQuotaExceeded:
+		return "ErrCodeQuotaExceeded"
+	case ErrCodeTransport:
+		return "ErrCodeTransport"
+	case ErrCodeInternal:
+		return "ErrCodeInternal"
+	default:
+		return fmt.Sprintf("ErrCode(%d)", int32(c))
+	}
+}
+
+// ZerobusError represents an error from the Zerobus SDK.
 type ZerobusError struct {
 	Message     string
 	IsRetryable bool
+
+	// Code categorizes this error; ErrCodeUnspecified if unknown.
+	Code ErrCode
+
+	// Cause, if set, is the underlying error this one wraps, returned by
+	// Unwrap so that errors.Is/As can see through it.
+	Cause error
 }
 
 func (e *ZerobusError) Error() string {
 	if e.IsRetryable {
-		return fmt.Sprintf("ZerobusError (retryable): %s", e.Message)
+		return fmt.Sprintf("ZerobusError (retryable, %s): %s", e.Code, e.Message)
 	}
-	return fmt.Sprintf("ZerobusError: %s", e.Message)
+	return fmt.Sprintf("ZerobusError (%s): %s", e.Code, e.Message)
 }
 
 // Retryable returns whether this error can be retried
 func (e *ZerobusError) Retryable() bool {
 	return e.IsRetryable
 }
+
+// Unwrap returns the underlying cause, if any, so errors.Is/As can traverse
+// into it.
+func (e *ZerobusError) Unwrap() error {
+	return e.Cause
+}
+
+// Is makes the sentinel ZerobusErrors (ErrAuth, ErrBackpressure, ...) work
+// with errors.Is: two *ZerobusError values match if they share a non-zero
+// Code, regardless of Message or Cause.
+func (e *ZerobusError) Is(target error) bool {
+	t, ok := target.(*ZerobusError)
+	if !ok || t.Code == ErrCodeUnspecified {
+		return false
+	}
+	return e.Code == t.Code
+}
+
+// Sentinel errors for each ErrCode, for use with errors.Is, e.g.
+// errors.Is(err, zerobus.ErrAuth).
+var (
+	ErrAuth           = &ZerobusError{Code: ErrCodeAuth, Message: "authentication failed"}
+	ErrSchemaMismatch = &ZerobusError{Code: ErrCodeSchemaMismatch, Message: "schema mismatch"}
+	ErrStreamClosed   = &ZerobusError{Code: ErrCodeStreamClosed, Message: "stream has been closed"}
+	ErrBackpressure   = &ZerobusError{Code: ErrCodeBackpressure, Message: "backpressure", IsRetryable: true}
+	ErrQuotaExceeded  = &ZerobusError{Code: ErrCodeQuotaExceeded, Message: "quota exceeded"}
+	ErrTransport      = &ZerobusError{Code: ErrCodeTransport, Message: "transport error", IsRetryable: true}
+	ErrInternal       = &ZerobusError{Code: ErrCodeInternal, Message: "internal error"}
+)
+
+// Code returns err's ErrCode if it is, or wraps, a *ZerobusError, and
+// ErrCodeUnspecified otherwise.
+func Code(err error) ErrCode {
+	var zbErr *ZerobusError
+	if errors.As(err, &zbErr) {
+		return zbErr.Code
+	}
+	return ErrCodeUnspecified
+}
+
+// IsRetryable returns whether err is, or wraps, a *ZerobusError marked
+// retryable. Like Code, it returns its zero value (false) for errors that
+// aren't ZerobusErrors at all.
+func IsRetryable(err error) bool {
+	var zbErr *ZerobusError
+	if errors.As(err, &zbErr) {
+		return zbErr.IsRetryable
+	}
+	return false
+}
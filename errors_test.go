@@ -0,0 +1,51 @@
+package zerobus
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestCodeAndIsRetryable verifies the Code/IsRetryable helpers see through
+// wrapping and return the zero value for non-ZerobusErrors.
+func TestCodeAndIsRetryable(t *testing.T) {
+	wrapped := fmt.Errorf("ingest failed: %w", ErrBackpressure)
+
+	if got := Code(wrapped); got != ErrCodeBackpressure {
+		t.Errorf("Code(wrapped) = %v, want %v", got, ErrCodeBackpressure)
+	}
+	if !IsRetryable(wrapped) {
+		t.Error("IsRetryable(wrapped) = false, want true")
+	}
+
+	plain := errors.New("not a ZerobusError")
+	if got := Code(plain); got != ErrCodeUnspecified {
+		t.Errorf("Code(plain) = %v, want ErrCodeUnspecified", got)
+	}
+	if IsRetryable(plain) {
+		t.Error("IsRetryable(plain) = true, want false")
+	}
+}
+
+// TestZerobusErrorIsMatchesByCode verifies errors.Is matches ZerobusErrors
+// sharing a Code regardless of Message or Cause.
+func TestZerobusErrorIsMatchesByCode(t *testing.T) {
+	err := &ZerobusError{Code: ErrCodeAuth, Message: "token expired"}
+
+	if !errors.Is(err, ErrAuth) {
+		t.Error("errors.Is(err, ErrAuth) = false, want true")
+	}
+	if errors.Is(err, ErrBackpressure) {
+		t.Error("errors.Is(err, ErrBackpressure) = true, want false")
+	}
+}
+
+// TestZerobusErrorUnwrap verifies Unwrap exposes Cause to errors.Is/As.
+func TestZerobusErrorUnwrap(t *testing.T) {
+	cause := errors.New("connection reset")
+	err := &ZerobusError{Code: ErrCodeTransport, Message: "transport failed", Cause: cause}
+
+	if !errors.Is(err, cause) {
+		t.Error("errors.Is(err, cause) = false, want true")
+	}
+}
@@ -0,0 +1,215 @@
+package ffibuild
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// EnsureRustLibrary checks if the Rust library exists for the module rooted
+// at the current working directory and builds it if needed. It's meant to
+// be run from the repo root, which is where internal/gen's go:generate
+// invocation (via build_rust.sh) leaves the working directory; application
+// code should run `go generate` instead of calling this directly.
+//
+// By default this tries to download a prebuilt, checksum-verified library for
+// the current GOOS/GOARCH first, since that turns `go build` into a
+// zero-dependency experience for the common case. It falls back to a local
+// `cargo build` (requiring the Rust toolchain) when the download fails or
+// when ZEROBUS_BUILD_FROM_SOURCE=1 is set. Set ZEROBUS_OFFLINE=1 in air-gapped
+// environments to skip the download attempt entirely and go straight to
+// cargo.
+func EnsureRustLibrary() error {
+	sdkDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to determine working directory: %w", err)
+	}
+	libPath := filepath.Join(sdkDir, "lib", fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH), "libzerobus_ffi.a")
+
+	// A cross-target build writes only under prebuilt/, so it runs whether or
+	// not libPath already exists or could be downloaded.
+	if os.Getenv("ZEROBUS_FFI_TARGETS") != "" {
+		return buildRustLibrary(sdkDir)
+	}
+
+	// Check if library already exists
+	if _, err := os.Stat(libPath); err == nil {
+		// Library exists, check if it needs rebuilding
+		if !needsRebuild(sdkDir, libPath) {
+			return nil
+		}
+	}
+
+	buildFromSource := os.Getenv("ZEROBUS_FFI_BUILD_FROM_SOURCE") == "1" || os.Getenv("ZEROBUS_BUILD_FROM_SOURCE") == "1"
+	offline := os.Getenv("ZEROBUS_OFFLINE") == "1"
+
+	if !buildFromSource && !offline {
+		fmt.Println("Downloading prebuilt Rust FFI library...")
+		if err := downloadPrebuiltLibrary(libPath); err != nil {
+			fmt.Printf("Prebuilt download unavailable (%v); building from source instead.\n", err)
+		} else {
+			return nil
+		}
+	}
+
+	fmt.Println("Building Rust FFI library (first time or after update)...")
+	fmt.Println("This may take 2-5 minutes...")
+
+	return buildRustLibrary(sdkDir)
+}
+
+// needsRebuild checks if any Rust source file is newer than the library
+func needsRebuild(sdkDir, libPath string) bool {
+	libInfo, err := os.Stat(libPath)
+	if err != nil {
+		return true
+	}
+
+	ffiDir := filepath.Join(sdkDir, "zerobus-ffi", "src")
+	needsRebuild := false
+
+	filepath.Walk(ffiDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if filepath.Ext(path) == ".rs" && info.ModTime().After(libInfo.ModTime()) {
+			needsRebuild = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+
+	return needsRebuild
+}
+
+// buildRustLibrary builds the Rust FFI library for the current GOOS/GOARCH.
+//
+// If ZEROBUS_FFI_TARGETS is set to a comma-separated list of Rust target
+// triples (e.g. "aarch64-apple-darwin,x86_64-unknown-linux-gnu"), it instead
+// cross-compiles for each of those targets via cargo-zigbuild, placing the
+// outputs under prebuilt/<triple>/libzerobus_ffi.a for later embedding (see
+// the zerobus_embed build tag in embed.go) rather than linking the current
+// build; this is for SDK maintainers preparing a release, not for end users
+// building their own application.
+func buildRustLibrary(sdkDir string) error {
+	if targets := os.Getenv("ZEROBUS_FFI_TARGETS"); targets != "" {
+		return buildRustLibraryForTargets(sdkDir, strings.Split(targets, ","))
+	}
+
+	ffiDir := filepath.Join(sdkDir, "zerobus-ffi")
+
+	// Check if Rust is installed
+	if _, err := exec.LookPath("cargo"); err != nil {
+		return fmt.Errorf("cargo not found. Install Rust from https://rustup.rs")
+	}
+
+	// Determine build command based on platform
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		// On Windows, build for GNU target to be compatible with MinGW
+		fmt.Println("Building for Windows GNU target (MinGW compatible)...")
+		cmd = exec.Command("cargo", "build", "--release", "--target", "x86_64-pc-windows-gnu")
+	} else if _, err := exec.LookPath("cargo-zigbuild"); err == nil {
+		fmt.Println("Using cargo-zigbuild for optimized cross-compilation...")
+		cmd = exec.Command("cargo", "zigbuild", "--release")
+	} else {
+		fmt.Println("Using cargo (install cargo-zigbuild for better cross-compilation)...")
+		cmd = exec.Command("cargo", "build", "--release")
+	}
+
+	cmd.Dir = ffiDir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("cargo build failed: %w", err)
+	}
+
+	// Copy library to SDK directory (handle multiple possible locations)
+	dstDir := filepath.Join(sdkDir, "lib", fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH))
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		return fmt.Errorf("failed to create library directory: %w", err)
+	}
+	dstLib := filepath.Join(dstDir, "libzerobus_ffi.a")
+
+	// Try different possible locations
+	possiblePaths := []string{
+		filepath.Join(ffiDir, "target", "release", "libzerobus_ffi.a"),
+		filepath.Join(ffiDir, "target", "x86_64-pc-windows-gnu", "release", "libzerobus_ffi.a"),
+		filepath.Join(ffiDir, "target", "release", "zerobus_ffi.lib"),
+	}
+
+	var data []byte
+	var err error
+	var srcLib string
+
+	for _, path := range possiblePaths {
+		data, err = os.ReadFile(path)
+		if err == nil {
+			srcLib = path
+			break
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to read built library (tried multiple locations): %w", err)
+	}
+
+	fmt.Printf("Using library: %s\n", srcLib)
+
+	if err := os.WriteFile(dstLib, data, 0644); err != nil {
+		return fmt.Errorf("failed to copy library: %w", err)
+	}
+
+	fmt.Println("✓ Rust FFI library built successfully")
+	return nil
+}
+
+// buildRustLibraryForTargets cross-compiles the Rust FFI library for each of
+// targets using cargo-zigbuild, writing each output to
+// prebuilt/<triple>/libzerobus_ffi.a.
+func buildRustLibraryForTargets(sdkDir string, targets []string) error {
+	if _, err := exec.LookPath("cargo-zigbuild"); err != nil {
+		return fmt.Errorf("ZEROBUS_FFI_TARGETS requires cargo-zigbuild: install it with " +
+			"'cargo install cargo-zigbuild'")
+	}
+
+	ffiDir := filepath.Join(sdkDir, "zerobus-ffi")
+
+	for _, target := range targets {
+		target = strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+
+		fmt.Printf("Building %s via cargo-zigbuild...\n", target)
+		cmd := exec.Command("cargo", "zigbuild", "--release", "--target", target)
+		cmd.Dir = ffiDir
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("cargo zigbuild --target %s failed: %w", target, err)
+		}
+
+		srcLib := filepath.Join(ffiDir, "target", target, "release", "libzerobus_ffi.a")
+		data, err := os.ReadFile(srcLib)
+		if err != nil {
+			return fmt.Errorf("reading library built for %s: %w", target, err)
+		}
+
+		dstDir := filepath.Join(sdkDir, "prebuilt", target)
+		if err := os.MkdirAll(dstDir, 0755); err != nil {
+			return fmt.Errorf("failed to create prebuilt directory for %s: %w", target, err)
+		}
+		if err := os.WriteFile(filepath.Join(dstDir, "libzerobus_ffi.a"), data, 0644); err != nil {
+			return fmt.Errorf("failed to write prebuilt library for %s: %w", target, err)
+		}
+
+		fmt.Printf("✓ Built %s -> prebuilt/%s/libzerobus_ffi.a\n", target, target)
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package ffibuild
+
+// prebuiltChecksums maps a "<GOOS>_<GOARCH>" target to the SHA-256 digest of
+// the libzerobus_ffi.a artifact published for sdkVersion. It is regenerated
+// by the release pipeline each time sdkVersion is bumped, mirroring the
+// per-target checksum pinning used for Bazel http_file downloads of native
+// artifacts, so a tampered or corrupted download is rejected rather than
+// silently linked in.
+//
+// This table ships empty until the release pipeline fills it in for a
+// tagged release: until then, downloadPrebuiltLibrary has no checksum to
+// verify against for any target and ffibuild.EnsureRustLibrary falls back to
+// a local `cargo build` for everyone, same as if ZEROBUS_OFFLINE=1 were set.
+var prebuiltChecksums = map[string]string{
+	// "linux_amd64":      "...",
+	// "linux_arm64":      "...",
+	// "linux_amd64_musl": "...",
+	// "linux_arm64_musl": "...",
+	// "darwin_amd64":     "...",
+	// "darwin_arm64":     "...",
+	// "windows_amd64":    "...",
+}
@@ -0,0 +1,155 @@
+// Package ffibuild downloads or builds the Rust libzerobus_ffi.a artifact
+// the root zerobus package links via cgo. It's a separate, cgo-free package
+// so that internal/gen (go:generate's entry point, via build_rust.sh) can
+// import and run it without first needing the very artifact it's fetching.
+package ffibuild
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// sdkVersion is bumped together with prebuiltChecksums each release.
+const sdkVersion = "v0.1.0"
+
+// defaultLibMirror is the base URL prebuilt libraries are downloaded from
+// when neither ZEROBUS_FFI_DOWNLOAD_URL nor the older ZEROBUS_LIB_MIRROR is
+// set.
+const defaultLibMirror = "https://github.com/databricks/zerobus-sdk-go/releases/download"
+
+// downloadTimeout bounds how long a single prebuilt-library download may take.
+const downloadTimeout = 60 * time.Second
+
+// libMirror resolves the configured release-artifact base URL.
+// ZEROBUS_FFI_DOWNLOAD_URL is the current name; ZEROBUS_LIB_MIRROR is
+// honored too for compatibility with existing deployments.
+func libMirror() string {
+	if v := os.Getenv("ZEROBUS_FFI_DOWNLOAD_URL"); v != "" {
+		return v
+	}
+	if v := os.Getenv("ZEROBUS_LIB_MIRROR"); v != "" {
+		return v
+	}
+	return defaultLibMirror
+}
+
+// targetTriple identifies the prebuilt artifact to fetch for the running
+// platform, as "<GOOS>_<GOARCH>" plus a "_musl" suffix on Linux when the
+// system's libc is musl rather than glibc (e.g. Alpine), since the two are
+// not ABI compatible.
+func targetTriple() string {
+	target := fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "linux" && isMuslLibc() {
+		target += "_musl"
+	}
+	return target
+}
+
+// isMuslLibc reports whether the running system links against musl libc
+// instead of glibc, using the same "ldd --version" sniff cargo and other
+// cross-compilation tooling rely on since there's no portable Go API for it.
+// ldd on musl systems exits non-zero for --version but still prints "musl
+// libc" to stdout/stderr, which CombinedOutput captures regardless, so a
+// non-nil err doesn't need different handling here.
+func isMuslLibc() bool {
+	out, _ := exec.Command("ldd", "--version").CombinedOutput()
+	return strings.Contains(strings.ToLower(string(out)), "musl")
+}
+
+// cachedLibraryPath returns where a downloaded prebuilt library for target
+// is cached across projects/builds, so repeated `go generate` runs (or
+// multiple modules vendoring the SDK) don't re-download the same artifact.
+func cachedLibraryPath(target string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving user cache dir: %w", err)
+	}
+	return filepath.Join(cacheDir, "zerobus", sdkVersion, target, "libzerobus_ffi.a"), nil
+}
+
+// downloadPrebuiltLibrary fetches the prebuilt libzerobus_ffi.a for the
+// current GOOS/GOARCH (and libc flavor), verifies it against
+// prebuiltChecksums, and writes it to libPath. A verified download is
+// cached under os.UserCacheDir()/zerobus/<version>/<target>/ and reused on
+// subsequent calls without hitting the network again.
+//
+// Returns an error (never partially writing libPath) if the target has no
+// pinned checksum, the download fails, or the checksum doesn't match —
+// callers should treat any error here as "fall back to building from
+// source".
+func downloadPrebuiltLibrary(libPath string) error {
+	target := targetTriple()
+
+	wantChecksum, ok := prebuiltChecksums[target]
+	if !ok || wantChecksum == "" {
+		return fmt.Errorf("no pinned checksum for target %q; not downloading an unverifiable artifact", target)
+	}
+
+	if cachePath, err := cachedLibraryPath(target); err == nil {
+		if data, err := os.ReadFile(cachePath); err == nil && verifyChecksum(data, wantChecksum) {
+			return installLibrary(libPath, data, target, "cache")
+		}
+	}
+
+	url := fmt.Sprintf("%s/%s/libzerobus_ffi_%s.a", libMirror(), sdkVersion, target)
+
+	client := &http.Client{Timeout: downloadTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("downloading %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading %s: unexpected status %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading downloaded library: %w", err)
+	}
+
+	if !verifyChecksum(data, wantChecksum) {
+		sum := sha256.Sum256(data)
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", url, hex.EncodeToString(sum[:]), wantChecksum)
+	}
+
+	if cachePath, err := cachedLibraryPath(target); err == nil {
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err == nil {
+			_ = os.WriteFile(cachePath, data, 0644)
+		}
+	}
+
+	return installLibrary(libPath, data, target, "download")
+}
+
+// verifyChecksum reports whether data's SHA-256 digest matches want.
+func verifyChecksum(data []byte, want string) bool {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]) == want
+}
+
+// installLibrary writes the verified library bytes to libPath, creating its
+// parent directory as needed. source is "cache" or "download", used only
+// for the log line.
+func installLibrary(libPath string, data []byte, target, source string) error {
+	if err := os.MkdirAll(filepath.Dir(libPath), 0755); err != nil {
+		return fmt.Errorf("failed to create library directory: %w", err)
+	}
+
+	if err := os.WriteFile(libPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write library: %w", err)
+	}
+
+	fmt.Printf("✓ Installed prebuilt Rust FFI library for %s from %s (sha256 verified)\n", target, source)
+	return nil
+}
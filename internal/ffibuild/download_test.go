@@ -0,0 +1,148 @@
+package ffibuild
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withFakeChecksums temporarily replaces prebuiltChecksums and restores the
+// original table afterwards.
+func withFakeChecksums(t *testing.T, fake map[string]string) {
+	t.Helper()
+	orig := prebuiltChecksums
+	prebuiltChecksums = fake
+	t.Cleanup(func() { prebuiltChecksums = orig })
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("libzerobus_ffi contents")
+	sum := sha256.Sum256(data)
+	want := hex.EncodeToString(sum[:])
+
+	if !verifyChecksum(data, want) {
+		t.Error("verifyChecksum(data, matching checksum) = false, want true")
+	}
+	if verifyChecksum(data, "deadbeef") {
+		t.Error("verifyChecksum(data, wrong checksum) = true, want false")
+	}
+}
+
+func TestLibMirrorPrecedence(t *testing.T) {
+	if got := libMirror(); got != defaultLibMirror {
+		t.Errorf("libMirror() = %q, want default %q", got, defaultLibMirror)
+	}
+
+	t.Setenv("ZEROBUS_LIB_MIRROR", "https://legacy.example.com")
+	if got := libMirror(); got != "https://legacy.example.com" {
+		t.Errorf("libMirror() = %q, want ZEROBUS_LIB_MIRROR value", got)
+	}
+
+	t.Setenv("ZEROBUS_FFI_DOWNLOAD_URL", "https://current.example.com")
+	if got := libMirror(); got != "https://current.example.com" {
+		t.Errorf("libMirror() = %q, want ZEROBUS_FFI_DOWNLOAD_URL to take precedence", got)
+	}
+}
+
+// TestDownloadPrebuiltLibraryVerifiesAndCaches drives downloadPrebuiltLibrary
+// against a fake mirror and a fake checksum table, verifying it installs
+// the library and caches it so a later call doesn't need the server at all.
+func TestDownloadPrebuiltLibraryVerifiesAndCaches(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	target := targetTriple()
+	data := []byte("fake libzerobus_ffi.a contents")
+	sum := sha256.Sum256(data)
+	withFakeChecksums(t, map[string]string{target: hex.EncodeToString(sum[:])})
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(data)
+	}))
+	defer server.Close()
+	t.Setenv("ZEROBUS_FFI_DOWNLOAD_URL", server.URL)
+
+	libPath := filepath.Join(t.TempDir(), "libzerobus_ffi.a")
+	if err := downloadPrebuiltLibrary(libPath); err != nil {
+		t.Fatalf("downloadPrebuiltLibrary() error = %v", err)
+	}
+
+	got, err := os.ReadFile(libPath)
+	if err != nil {
+		t.Fatalf("reading installed library: %v", err)
+	}
+	if string(got) != string(data) {
+		t.Errorf("installed library contents = %q, want %q", got, data)
+	}
+	if requests != 1 {
+		t.Fatalf("server received %d requests, want 1", requests)
+	}
+
+	// A second call with a fresh destination should be served from the
+	// cache populated above, without hitting the server again.
+	libPath2 := filepath.Join(t.TempDir(), "libzerobus_ffi.a")
+	if err := downloadPrebuiltLibrary(libPath2); err != nil {
+		t.Fatalf("downloadPrebuiltLibrary() (cached) error = %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("server received %d requests after cached call, want still 1", requests)
+	}
+	got2, err := os.ReadFile(libPath2)
+	if err != nil {
+		t.Fatalf("reading cached-installed library: %v", err)
+	}
+	if string(got2) != string(data) {
+		t.Errorf("cached library contents = %q, want %q", got2, data)
+	}
+}
+
+// TestDownloadPrebuiltLibraryRejectsChecksumMismatch verifies a downloaded
+// artifact that doesn't match the pinned checksum is rejected and never
+// installed.
+func TestDownloadPrebuiltLibraryRejectsChecksumMismatch(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	target := targetTriple()
+	withFakeChecksums(t, map[string]string{target: "0000000000000000000000000000000000000000000000000000000000000000"})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("unexpected contents"))
+	}))
+	defer server.Close()
+	t.Setenv("ZEROBUS_FFI_DOWNLOAD_URL", server.URL)
+
+	libPath := filepath.Join(t.TempDir(), "libzerobus_ffi.a")
+	if err := downloadPrebuiltLibrary(libPath); err == nil {
+		t.Fatal("downloadPrebuiltLibrary() error = nil, want checksum mismatch error")
+	}
+	if _, err := os.Stat(libPath); !os.IsNotExist(err) {
+		t.Errorf("libPath exists after checksum mismatch, want it never written")
+	}
+}
+
+// TestDownloadPrebuiltLibraryNoChecksumPinned verifies a target missing from
+// prebuiltChecksums is rejected without attempting a download.
+func TestDownloadPrebuiltLibraryNoChecksumPinned(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	withFakeChecksums(t, map[string]string{})
+
+	var requested bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+	t.Setenv("ZEROBUS_FFI_DOWNLOAD_URL", server.URL)
+
+	libPath := filepath.Join(t.TempDir(), "libzerobus_ffi.a")
+	if err := downloadPrebuiltLibrary(libPath); err == nil {
+		t.Fatal("downloadPrebuiltLibrary() error = nil, want \"no pinned checksum\" error")
+	}
+	if requested {
+		t.Error("server was contacted despite no pinned checksum for the target")
+	}
+}
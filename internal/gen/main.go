@@ -0,0 +1,19 @@
+// Command gen drives internal/ffibuild.EnsureRustLibrary from
+// build_rust.sh's go:generate directive. It calls straight into ffibuild
+// rather than the root zerobus package so that running this tool doesn't
+// itself require libzerobus_ffi.a to already be linkable.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/databricks/zerobus-sdk-go/internal/ffibuild"
+)
+
+func main() {
+	if err := ffibuild.EnsureRustLibrary(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
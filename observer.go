@@ -0,0 +1,78 @@
+package zerobus
+
+import "time"
+
+// Observer receives callbacks about stream activity, giving visibility into
+// inflight depth, ack latency, and recovery events without requiring callers
+// to poll TryGet or parse error strings. All methods are called synchronously
+// from the goroutine driving the corresponding operation, so implementations
+// must not block and must be safe for concurrent use.
+//
+// Set via StreamConfigurationOptions.Observer; leave nil to disable (the
+// default). NewExpvarObserver provides a stdlib-only adapter; the
+// contrib/zerobusprom and contrib/zerobusotel subdirectories provide
+// Prometheus and OpenTelemetry adapters as separate modules so that neither
+// dependency is forced on callers who don't use it.
+type Observer interface {
+	// OnRecordQueued is called after a record has been handed to the FFI
+	// layer and assigned ackID, with the size of its encoded payload. The
+	// same ackID is later passed to OnRecordAcked, so implementations that
+	// need to correlate the two calls (e.g. to end a per-record span) can
+	// key off of it directly instead of assuming queue order.
+	OnRecordQueued(ackID uint64, size int)
+
+	// OnRecordAcked is called once per record, when its acknowledgment is
+	// observed via RecordAck.Await or AwaitContext (including through Done,
+	// WaitAll and WaitAny), or when a failed batch ingest discards its
+	// already-queued RecordAck. TryGet does not report to the Observer.
+	// latency is the time between the record being queued and this callback firing, not
+	// necessarily the server-side ack latency.
+	OnRecordAcked(ackID uint64, offset int64, latency time.Duration)
+
+	// OnFlush is called after a Flush/FlushContext call completes, whether
+	// it succeeded or not. pending is the number of records that were
+	// in-flight when Flush was invoked.
+	OnFlush(pending int, duration time.Duration, err error)
+
+	// OnRecovery is called for each Go-side retry performed by
+	// retryOnRecoverable (stream (re)connection and Await retries) or by
+	// AwaitContext, after attempt has failed with err.
+	OnRecovery(attempt int, err error)
+
+	// OnStreamError is called when a *ZerobusError is returned by
+	// IngestRecord, CreateStream, CreateStreamWithHeadersProvider or Await
+	// (including ones that OnRecovery will go on to retry), and for each
+	// retryable error that AwaitContext retries. Errors from the batch ingest
+	// methods, Flush, FlushContext and Close, and the error AwaitContext
+	// finally returns, are not reported here.
+	OnStreamError(err *ZerobusError)
+}
+
+// NoopObserver implements Observer with no-op methods. Embed it to implement
+// Observer while only overriding the callbacks you care about.
+type NoopObserver struct{}
+
+func (NoopObserver) OnRecordQueued(ackID uint64, size int) {}
+
+func (NoopObserver) OnRecordAcked(ackID uint64, offset int64, latency time.Duration) {}
+
+func (NoopObserver) OnFlush(pending int, duration time.Duration, err error) {}
+
+func (NoopObserver) OnRecovery(attempt int, err error) {}
+
+func (NoopObserver) OnStreamError(err *ZerobusError) {}
+
+// observerFor returns the configured Observer for options, or NoopObserver{}
+// if none was set, so call sites can invoke callbacks unconditionally.
+func observerFor(options *StreamConfigurationOptions) Observer {
+	if options != nil && options.Observer != nil {
+		return options.Observer
+	}
+	return NoopObserver{}
+}
+
+// observer returns the stream's configured Observer, or NoopObserver{} if
+// none was set.
+func (st *ZerobusStream) observer() Observer {
+	return observerFor(st.options)
+}
@@ -0,0 +1,76 @@
+package zerobus
+
+import (
+	"expvar"
+	"sync/atomic"
+	"time"
+)
+
+// ExpvarObserver is an Observer that publishes stream activity as expvar
+// metrics, viewable at the process's /debug/vars endpoint.
+//
+// All counters here are published under a single *expvar.Map (see
+// NewExpvarObserver) so that multiple streams can share one
+// ExpvarObserver (e.g. to get an aggregate view across a pool of streams)
+// without colliding on global expvar names.
+type ExpvarObserver struct {
+	inflight    int64
+	queued      expvar.Int
+	acked       expvar.Int
+	ackLatency  expvar.Int // cumulative, nanoseconds; divide by acked for an average
+	flushes     expvar.Int
+	flushErrors expvar.Int
+	recoveries  expvar.Int
+	errors      expvar.Int
+}
+
+// NewExpvarObserver creates an ExpvarObserver and publishes its counters
+// under expvar.Publish(name, ...) as a *expvar.Map with keys "inflight",
+// "queued", "acked", "ack_latency_ns", "flushes", "flush_errors",
+// "recoveries", and "errors".
+//
+// Publishing panics if name is already registered, per expvar.Publish, so
+// callers constructing more than one ExpvarObserver in the same process must
+// use distinct names.
+func NewExpvarObserver(name string) *ExpvarObserver {
+	o := &ExpvarObserver{}
+
+	m := new(expvar.Map)
+	m.Set("inflight", expvar.Func(func() interface{} { return atomic.LoadInt64(&o.inflight) }))
+	m.Set("queued", &o.queued)
+	m.Set("acked", &o.acked)
+	m.Set("ack_latency_ns", &o.ackLatency)
+	m.Set("flushes", &o.flushes)
+	m.Set("flush_errors", &o.flushErrors)
+	m.Set("recoveries", &o.recoveries)
+	m.Set("errors", &o.errors)
+	expvar.Publish(name, m)
+
+	return o
+}
+
+func (o *ExpvarObserver) OnRecordQueued(ackID uint64, size int) {
+	atomic.AddInt64(&o.inflight, 1)
+	o.queued.Add(1)
+}
+
+func (o *ExpvarObserver) OnRecordAcked(ackID uint64, offset int64, latency time.Duration) {
+	atomic.AddInt64(&o.inflight, -1)
+	o.acked.Add(1)
+	o.ackLatency.Add(latency.Nanoseconds())
+}
+
+func (o *ExpvarObserver) OnFlush(pending int, duration time.Duration, err error) {
+	o.flushes.Add(1)
+	if err != nil {
+		o.flushErrors.Add(1)
+	}
+}
+
+func (o *ExpvarObserver) OnRecovery(attempt int, err error) {
+	o.recoveries.Add(1)
+}
+
+func (o *ExpvarObserver) OnStreamError(err *ZerobusError) {
+	o.errors.Add(1)
+}
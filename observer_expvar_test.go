@@ -0,0 +1,40 @@
+package zerobus
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestExpvarObserverTracksInflight verifies the inflight counter increments
+// on queue and decrements on ack.
+func TestExpvarObserverTracksInflight(t *testing.T) {
+	o := NewExpvarObserver("zerobus_test_inflight")
+
+	o.OnRecordQueued(1, 10)
+	o.OnRecordQueued(2, 20)
+	if got := o.inflight; got != 2 {
+		t.Errorf("inflight = %d, want 2", got)
+	}
+
+	o.OnRecordAcked(1, 100, time.Millisecond)
+	if got := o.inflight; got != 1 {
+		t.Errorf("inflight = %d, want 1", got)
+	}
+}
+
+// TestExpvarObserverCountsFlushErrors verifies flush_errors only increments
+// when OnFlush observes a non-nil error.
+func TestExpvarObserverCountsFlushErrors(t *testing.T) {
+	o := NewExpvarObserver("zerobus_test_flush_errors")
+
+	o.OnFlush(0, time.Millisecond, nil)
+	o.OnFlush(0, time.Millisecond, errors.New("boom"))
+
+	if got := o.flushes.Value(); got != 2 {
+		t.Errorf("flushes = %d, want 2", got)
+	}
+	if got := o.flushErrors.Value(); got != 1 {
+		t.Errorf("flushErrors = %d, want 1", got)
+	}
+}
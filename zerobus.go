@@ -6,12 +6,26 @@
 //
 // # Installation
 //
-// This package requires a one-time build step to compile the Rust FFI layer:
+// This package requires a one-time build step to fetch the Rust FFI layer:
 //
 //	go get github.com/databricks/zerobus-sdk-go
 //	go generate github.com/databricks/zerobus-sdk-go
 //
-// Prerequisites: Go 1.19+, Rust 1.70+, CGO enabled
+// By default this downloads a prebuilt, checksum-verified library for your
+// platform (including musl vs. glibc detection on Linux), caching it under
+// os.UserCacheDir()/zerobus/<version>/ so later builds skip the network
+// entirely. No Rust toolchain is required for a release that has shipped
+// pinned checksums for your target in internal/ffibuild's prebuiltChecksums
+// (see internal/ffibuild/checksums.go);
+// until the release pipeline populates that table, every target falls back
+// to a local `cargo build` instead. It falls back the same way if no
+// prebuilt artifact is available for your GOOS/GOARCH, or always builds
+// from source if ZEROBUS_FFI_BUILD_FROM_SOURCE=1 is set. Set
+// ZEROBUS_FFI_DOWNLOAD_URL to point at an internal mirror of the release
+// artifacts (for air-gapped or proxied environments), and ZEROBUS_OFFLINE=1
+// to skip the download attempt entirely.
+//
+// Prerequisites: Go 1.19+, CGO enabled, and (source builds only) Rust 1.70+
 //
 // # Quick Start
 //
@@ -82,15 +96,20 @@
 //
 // # Error Handling
 //
-// Errors are categorized as retryable or non-retryable:
+// Errors are *zerobus.ZerobusError, categorized by both retryability and an
+// ErrCode so callers can branch on the failure kind instead of matching
+// Message strings:
 //
 //	ack, err := stream.IngestRecord(data)
 //	if err != nil {
-//	    if zbErr, ok := err.(*zerobus.ZerobusError); ok {
-//	        if zbErr.Retryable() {
-//	            // Transient error, SDK will auto-recover
-//	        } else {
-//	            // Fatal error, manual intervention needed
+//	    switch zerobus.Code(err) {
+//	    case zerobus.ErrCodeAuth:
+//	        // refresh credentials and retry
+//	    case zerobus.ErrCodeBackpressure:
+//	        // slow down; zerobus.IsRetryable(err) will also be true here
+//	    default:
+//	        if zerobus.IsRetryable(err) {
+//	            // transient error, SDK will auto-recover
 //	        }
 //	    }
 //	}
@@ -109,6 +128,20 @@
 //	    offset, _ := ack.Await()
 //	}
 //
+// # Context and Cancellation
+//
+// Context-aware variants of the blocking calls above are available, suffixed
+// with "Context" (NewZerobusSdkContext, CreateStreamContext,
+// IngestRecordContext, FlushContext, CloseContext, RecordAck.AwaitContext).
+// Cancellation semantics vary by call: FlushContext polls the underlying
+// Rust call cooperatively and aborts it in place, CloseContext frees the
+// stream at once without the final flush, AwaitContext stops polling for
+// the ack, and the others simply stop waiting on a background goroutine
+// that may still be completing the
+// operation (so, e.g., a canceled IngestRecordContext may still be persisted
+// server-side, but the caller has no way to retrieve its RecordAck). See the
+// doc comment on each Context method for the exact guarantee it makes.
+//
 // # Static Linking
 //
 // This SDK uses static linking of the Rust FFI layer, resulting in self-contained
@@ -119,6 +152,9 @@ package zerobus
 
 import (
 	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -131,7 +167,14 @@ type ZerobusSdk struct {
 // ZerobusStream represents an active bidirectional gRPC stream for ingesting records.
 // Records can be ingested concurrently and will be acknowledged asynchronously.
 type ZerobusStream struct {
-	ptr unsafe.Pointer
+	ptr     unsafe.Pointer
+	options *StreamConfigurationOptions
+	closeMu sync.Mutex
+
+	// inflight is a best-effort count of queued-but-not-yet-acknowledged
+	// records, maintained only to report StreamConfigurationOptions.Observer
+	// callbacks; it is not used for flow control.
+	inflight int64
 }
 
 // NewZerobusSdk creates a new SDK instance.
@@ -202,22 +245,24 @@ func (s *ZerobusSdk) CreateStream(
 	options *StreamConfigurationOptions,
 ) (*ZerobusStream, error) {
 	if s.ptr == nil {
-		return nil, &ZerobusError{Message: "SDK has been freed", IsRetryable: false}
+		return nil, &ZerobusError{Message: "SDK has been freed", IsRetryable: false, Code: ErrCodeStreamClosed}
 	}
 
-	ptr, err := sdkCreateStream(
-		s.ptr,
-		tableProps.TableName,
-		tableProps.DescriptorProto,
-		clientID,
-		clientSecret,
-		options,
-	)
+	ptr, err := retryOnRecoverable(options, func() (unsafe.Pointer, error) {
+		return sdkCreateStream(
+			s.ptr,
+			tableProps.TableName,
+			tableProps.DescriptorProto,
+			clientID,
+			clientSecret,
+			options,
+		)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	stream := &ZerobusStream{ptr: ptr}
+	stream := &ZerobusStream{ptr: ptr, options: options}
 
 	// Set up finalizer for automatic cleanup
 	runtime.SetFinalizer(stream, func(st *ZerobusStream) {
@@ -273,21 +318,23 @@ func (s *ZerobusSdk) CreateStreamWithHeadersProvider(
 	options *StreamConfigurationOptions,
 ) (*ZerobusStream, error) {
 	if s.ptr == nil {
-		return nil, &ZerobusError{Message: "SDK has been freed", IsRetryable: false}
+		return nil, &ZerobusError{Message: "SDK has been freed", IsRetryable: false, Code: ErrCodeStreamClosed}
 	}
 
-	ptr, err := sdkCreateStreamWithHeadersProvider(
-		s.ptr,
-		tableProps.TableName,
-		tableProps.DescriptorProto,
-		headersProvider,
-		options,
-	)
+	ptr, err := retryOnRecoverable(options, func() (unsafe.Pointer, error) {
+		return sdkCreateStreamWithHeadersProvider(
+			s.ptr,
+			tableProps.TableName,
+			tableProps.DescriptorProto,
+			headersProvider,
+			options,
+		)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	stream := &ZerobusStream{ptr: ptr}
+	stream := &ZerobusStream{ptr: ptr, options: options}
 
 	// Set up finalizer for automatic cleanup
 	runtime.SetFinalizer(stream, func(st *ZerobusStream) {
@@ -324,16 +371,19 @@ func (s *ZerobusSdk) CreateStreamWithHeadersProvider(
 //	offset3, err3 := ack3.Await()
 func (st *ZerobusStream) IngestRecord(payload interface{}) (*RecordAck, error) {
 	if st.ptr == nil {
-		return nil, &ZerobusError{Message: "Stream has been closed", IsRetryable: false}
+		return nil, &ZerobusError{Message: "Stream has been closed", IsRetryable: false, Code: ErrCodeStreamClosed}
 	}
 
 	var ackID uint64
 	var err error
+	var size int
 
 	switch v := payload.(type) {
 	case []byte:
+		size = len(v)
 		ackID, err = streamIngestProtoRecord(st.ptr, v)
 	case string:
+		size = len(v)
 		ackID, err = streamIngestJSONRecord(st.ptr, v)
 	default:
 		return nil, &ZerobusError{
@@ -343,12 +393,29 @@ func (st *ZerobusStream) IngestRecord(payload interface{}) (*RecordAck, error) {
 	}
 
 	if err != nil {
+		if zbErr, ok := err.(*ZerobusError); ok {
+			st.observer().OnStreamError(zbErr)
+		}
 		return nil, err
 	}
 
+	return st.newAck(ackID, size), nil
+}
+
+// newAck builds the RecordAck for a just-queued record, reporting it to the
+// stream's Observer and best-effort inflight count.
+func (st *ZerobusStream) newAck(ackID uint64, size int) *RecordAck {
+	atomic.AddInt64(&st.inflight, 1)
+	observer := st.observer()
+	observer.OnRecordQueued(ackID, size)
+
 	return &RecordAck{
-		ackID: ackID,
-	}, nil
+		ackID:    ackID,
+		options:  st.options,
+		observer: observer,
+		stream:   st,
+		queuedAt: time.Now(),
+	}
 }
 
 // Flush blocks until all pending records have been acknowledged by the server.
@@ -365,10 +432,41 @@ func (st *ZerobusStream) IngestRecord(payload interface{}) (*RecordAck, error) {
 //	}
 func (st *ZerobusStream) Flush() error {
 	if st.ptr == nil {
-		return &ZerobusError{Message: "Stream has been closed", IsRetryable: false}
+		return &ZerobusError{Message: "Stream has been closed", IsRetryable: false, Code: ErrCodeStreamClosed}
 	}
 
-	return streamFlush(st.ptr)
+	pending := int(atomic.LoadInt64(&st.inflight))
+	start := time.Now()
+	err := streamFlush(st.ptr)
+	st.observer().OnFlush(pending, time.Since(start), err)
+
+	return err
+}
+
+// streamCloseFn and streamFreeFn indirect the FFI calls Close/CloseContext
+// depend on so tests can substitute a fake stream without going through
+// CGo; production code always leaves them pointing at the real functions.
+var (
+	streamCloseFn = streamClose
+	streamFreeFn  = streamFree
+)
+
+// claimPtrForClose atomically takes ownership of st.ptr, returning it (and
+// true) the first time it's called for a given stream, after which st.ptr
+// is nil so IngestRecord/Flush/etc. immediately see the stream as closed.
+// Later calls — whether from Close() or a CloseContext cancellation racing
+// it — get (nil, false), since the stream is already closed or in the
+// process of being closed by whoever won the race. The lock is only held
+// long enough to swap the pointer, never for the duration of the blocking
+// streamClose/streamFree FFI calls, so it can't stall a concurrent
+// CloseContext waiting to force the stream closed on ctx cancellation.
+func (st *ZerobusStream) claimPtrForClose() (unsafe.Pointer, bool) {
+	st.closeMu.Lock()
+	defer st.closeMu.Unlock()
+
+	ptr := st.ptr
+	st.ptr = nil
+	return ptr, ptr != nil
 }
 
 // Close gracefully closes the stream after flushing all pending records.
@@ -386,13 +484,13 @@ func (st *ZerobusStream) Flush() error {
 //
 //	defer stream.Close()
 func (st *ZerobusStream) Close() error {
-	if st.ptr == nil {
+	ptr, ok := st.claimPtrForClose()
+	if !ok {
 		return nil // Already closed
 	}
 
-	err := streamClose(st.ptr)
-	streamFree(st.ptr)
-	st.ptr = nil
+	err := streamCloseFn(ptr)
+	streamFreeFn(ptr)
 
 	return err
 }